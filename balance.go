@@ -0,0 +1,177 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt" // Zum Formatieren der Warnungen.
+
+	"github.com/pelletier/go-toml" // Zum Lesen der [[ausgleich]]-Tabellen.
+)
+
+// ############################################################################################
+// balanceSection ist der Name der Tabellen-Liste für Ausgleichsregeln ([[ausgleich]]).
+const balanceSection = "ausgleich"
+
+// balanceKeys sind die erlaubten Schlüssel einer Ausgleichsregel.
+var balanceKeys = []string{"merkmal", "wert", "hoechstens", "mischen", "gewicht"}
+
+// ############################################################################################
+// balanceRule beschreibt eine Ausgleichsregel für ein Merkmal der Schüler. Beispiele:
+// 		[[ausgleich]]
+// 		merkmal = "niveau"
+// 		wert = "stark"
+// 		hoechstens = 1      # feste Regel: höchstens 1 starker Schüler pro Gruppe
+//
+// 		[[ausgleich]]
+// 		merkmal = "geschlecht"
+// 		mischen = true      # weiche Regel: Gruppen möglichst gemischt
+// 		gewicht = 2
+type balanceRule struct {
+	Attribute   string // Name des Merkmals (z.B. "niveau").
+	Value       string // Wert, der begrenzt wird (nur zusammen mit 'MaxPerGroup').
+	MaxPerGroup int    // Feste Regel: höchstens so viele Schüler mit diesem Wert pro Gruppe (0 = keine Begrenzung).
+	Mix         bool   // Weiche Regel: Die Werte des Merkmals sollen in jeder Gruppe möglichst gemischt sein.
+	Weight      int    // Gewicht der weichen Regel (Standard 1).
+}
+
+// ############################################################################################
+// readBalanceRules liest alle [[ausgleich]]-Tabellen ein.
+// Unvollständige Regeln werden übersprungen und wie falsche Typen im Prüfbericht gemeldet.
+func readBalanceRules(tree *toml.Tree, report *validationReport) []balanceRule {
+	var rules []balanceRule
+	val := tree.Get(balanceSection)
+	if val == nil {
+		return rules // Abschnitt ist optional.
+	}
+	tables, ok := val.([]*toml.Tree)
+	if !ok {
+		report.add(report.position("", balanceSection), issueWrongType, "'%s' muss als Liste von Tabellen [[%s]] angegeben werden, gefunden: %s", balanceSection, balanceSection, describeType(val))
+		return rules
+	}
+
+	for i, table := range tables {
+		where := fmt.Sprintf("Ausgleichsregel %d", i+1)
+		checkKnownKeys(table, balanceKeys, where, report)
+		at := func(key string) toml.Position { // Fundstelle eines Schlüssels, sonst der Tabelle.
+			if position := table.GetPositionPath([]string{key}); !position.Invalid() {
+				return position
+			}
+			return table.Position()
+		}
+
+		rule := balanceRule{Weight: 1}
+		attribute, isString := table.Get("merkmal").(string)
+		if !isString || attribute == "" {
+			if table.Has("merkmal") {
+				report.add(at("merkmal"), issueWrongType, "%s: 'merkmal' muss ein Text sein, gefunden: %s", where, describeType(table.Get("merkmal")))
+			} else {
+				report.add(at("merkmal"), issueInvalidRule, "%s hat kein 'merkmal' und wird ignoriert", where)
+			}
+			continue
+		}
+		rule.Attribute = attribute
+		if value := table.Get("wert"); value != nil {
+			rule.Value = formatAttributeValue(value)
+		}
+		if maxValue := table.Get("hoechstens"); maxValue != nil {
+			if maxCount, isInt := maxValue.(int64); isInt {
+				rule.MaxPerGroup = int(maxCount)
+			} else {
+				report.add(at("hoechstens"), issueWrongType, "%s: 'hoechstens' muss eine ganze Zahl sein, gefunden: %s", where, describeType(maxValue))
+			}
+		}
+		if mixValue := table.Get("mischen"); mixValue != nil {
+			if mix, isBool := mixValue.(bool); isBool {
+				rule.Mix = mix
+			} else {
+				report.add(at("mischen"), issueWrongType, "%s: 'mischen' muss true oder false sein, gefunden: %s", where, describeType(mixValue))
+			}
+		}
+		if weightValue := table.Get("gewicht"); weightValue != nil {
+			if weight, isInt := weightValue.(int64); isInt && weight > 0 {
+				rule.Weight = int(weight)
+			} else {
+				report.add(at("gewicht"), issueWrongType, "%s: 'gewicht' muss eine ganze Zahl größer 0 sein, gefunden: %s", where, describeType(weightValue))
+			}
+		}
+
+		if rule.MaxPerGroup > 0 && rule.Value == "" {
+			report.add(table.Position(), issueInvalidRule, "%s ('%s') braucht für 'hoechstens' einen 'wert' und wird ignoriert", where, rule.Attribute)
+			continue
+		}
+		if rule.MaxPerGroup <= 0 && !rule.Mix {
+			report.add(table.Position(), issueInvalidRule, "%s ('%s') braucht 'hoechstens' (größer 0) oder 'mischen = true' und wird ignoriert", where, rule.Attribute)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ############################################################################################
+// describe gibt eine Regel in Worten zurück (für Ausgaben und Hinweise).
+func (b balanceRule) describe() string {
+	if b.MaxPerGroup > 0 {
+		return fmt.Sprintf("höchstens %d mit %s = '%s' pro Gruppe", b.MaxPerGroup, b.Attribute, b.Value)
+	}
+	return fmt.Sprintf("Gruppen gemischt nach '%s'", b.Attribute)
+}
+
+// ############################################################################################
+// exceedsBalanceLimits prüft die festen Ausgleichsregeln ('hoechstens') für eine Gruppe.
+// Die Prüfung gilt auch für unvollständige Gruppen: Wird eine Grenze überschritten,
+// 		kann kein weiteres Mitglied das wieder gutmachen.
+func (r *groupRules) exceedsBalanceLimits(group []string) bool {
+	for _, rule := range r.balance {
+		if rule.MaxPerGroup <= 0 {
+			continue
+		}
+		count := 0
+		for _, student := range group {
+			if r.attribute(student, rule.Attribute) == rule.Value {
+				count++
+			}
+		}
+		if count > rule.MaxPerGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// ############################################################################################
+// balancePenalty berechnet den Abzug für ungleich gemischte Gruppen (Regeln mit 'mischen').
+// Pro Gruppe wird gezählt, wie viele Schüler mit dem häufigsten Wert über einer idealen,
+// 		gleichmäßigen Verteilung liegen. Beispiel bei zwei Werten: 2/2 ergibt 0, 3/1 ergibt 1, 4/0 ergibt 2.
+// Schüler ohne das Merkmal werden nicht mitgezählt.
+func (r *groupRules) balancePenalty(groups [][]string) int {
+	penalty := 0
+	for _, rule := range r.balance {
+		if !rule.Mix {
+			continue
+		}
+		valueCount := len(r.attributeValues[rule.Attribute]) // Anzahl verschiedener Werte in der Klasse.
+		if valueCount < 2 {
+			continue // Ohne verschiedene Werte gibt es nichts zu mischen.
+		}
+		for _, group := range groups {
+			counts := make(map[string]int)
+			members, largest := 0, 0
+			for _, student := range group {
+				value := r.attribute(student, rule.Attribute)
+				if value == "" {
+					continue
+				}
+				members++
+				counts[value]++
+				if counts[value] > largest {
+					largest = counts[value]
+				}
+			}
+			ideal := (members + valueCount - 1) / valueCount // Aufgerundet: so viele pro Wert bei idealer Mischung.
+			if largest > ideal {
+				penalty += (largest - ideal) * rule.Weight
+			}
+		}
+	}
+	return penalty
+}
@@ -0,0 +1,170 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"bufio"         // Zum Lesen ganzer Zeilen (Klassennamen dürfen Leerzeichen enthalten).
+	"fmt"           // Für das Auswahlmenü und die Fehlermeldungen.
+	"io"            // Zum Erkennen, dass keine Eingabe mehr kommt.
+	"os"            // Zum Suchen des Klassen-Ordners.
+	"path/filepath" // Zum Zusammensetzen der Pfade.
+	"sort"          // Für eine alphabetische Liste der Klassen.
+	"strconv"       // Zum Lesen der Auswahl im Menü.
+	"strings"       // Zum Vergleichen der Klassennamen.
+)
+
+// ############################################################################################
+// classesDirName ist der Ordner, in dem mehrere Klassen als eigene Dateien liegen (z.B. 'klassen/7b.toml').
+// Er wird wie 'klasse.toml' zuerst im aktuellen Verzeichnis und dann neben dem Programm gesucht.
+const classesDirName = "klassen"
+
+// ############################################################################################
+// selectClass bestimmt die Konfigurationsdatei, wenn mit mehreren Klassen gearbeitet wird.
+// Die Klassen liegen als einzelne Dateien in einem Ordner: entweder 'klassen' (automatisch gefunden)
+// 		oder ein Ordner, der mit '-datei' angegeben wird. Gewählt wird die Klasse mit '-klasse 7b',
+// 		sonst über ein Menü (gibt es nur eine Klasse, wird sie direkt verwendet).
+// Ohne Klassen-Ordner bleibt alles wie bisher: Es wird die einzelne 'klasse.toml' verwendet.
+func selectClass(options *cliOptions) error {
+	dir := ""
+	if info, err := os.Stat(options.ConfigPath); err == nil && info.IsDir() { // '-datei' zeigt auf einen Ordner.
+		dir = options.ConfigPath
+	} else if options.ConfigPath == defaultConfigName {
+		dir = findClassesDir()
+	}
+
+	if dir == "" {
+		if options.ClassName == "" {
+			return nil // Wie bisher: eine einzelne Konfigurationsdatei.
+		}
+		if options.Command != "erstellen" {
+			return fmt.Errorf("❌ Für '-klasse' fehlt der Ordner '%s' mit den Klassen (z.B. '%s/7b.toml'). Tipp: 'klassenmischer erstellen -klasse %s' legt ihn an", classesDirName, classesDirName, options.ClassName)
+		}
+		dir = classesDirName // Der Ordner wird beim Erstellen der ersten Klasse angelegt.
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("❌ Fehler beim Anlegen des Ordners '%s': %w", dir, err)
+		}
+	}
+
+	classes, err := listClasses(dir)
+	if err != nil {
+		return err
+	}
+
+	if options.ClassName != "" { // Klasse wurde mit '-klasse' angegeben.
+		for _, class := range classes {
+			if strings.EqualFold(class, strings.TrimSuffix(options.ClassName, ".toml")) {
+				options.ConfigPath, options.ClassName = classPath(dir, class), class
+				return nil
+			}
+		}
+		if options.Command == "erstellen" { // Neue Klasse anlegen.
+			options.ConfigPath = classPath(dir, strings.TrimSuffix(options.ClassName, ".toml"))
+			return nil
+		}
+		return fmt.Errorf("❌ Klasse '%s' nicht gefunden in '%s'. Vorhandene Klassen: %s", options.ClassName, dir, describeClasses(classes))
+	}
+
+	switch {
+	case options.Command == "erstellen" || len(classes) == 0:
+		if options.ConfigPath == defaultConfigName {
+			return nil // Leerer Klassen-Ordner: wie bisher 'klasse.toml'.
+		}
+		return fmt.Errorf("❌ Im Ordner '%s' liegen keine Klassen (*.toml). Tipp: 'klassenmischer erstellen -datei %s -klasse 7b'", dir, dir)
+	case len(classes) == 1:
+		options.ConfigPath, options.ClassName = classPath(dir, classes[0]), classes[0]
+		return nil
+	case options.Batch:
+		return fmt.Errorf("❌ Mehrere Klassen gefunden, bitte mit '-klasse' auswählen: %s", describeClasses(classes))
+	}
+
+	class, err := chooseClassFromMenu(classes)
+	if err != nil {
+		return err
+	}
+	options.ConfigPath, options.ClassName = classPath(dir, class), class
+	return nil
+}
+
+// ############################################################################################
+// findClassesDir sucht den Ordner 'klassen' im aktuellen Verzeichnis und neben dem Programm
+// 		(gleiche Reihenfolge wie bei 'klasse.toml'). Gibt "" zurück, wenn es keinen gibt.
+func findClassesDir() string {
+	var candidates []string
+	if cwd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, filepath.Join(cwd, classesDirName))
+	}
+	if execPath, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(execPath), classesDirName))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// listClasses gibt die Namen aller Klassen im Ordner zurück (Dateinamen ohne '.toml', alphabetisch).
+// Verlaufsdateien ('-verlauf.toml') gehören zu einer Klasse und werden nicht aufgelistet.
+func listClasses(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Fehler beim Lesen des Klassen-Ordners '%s': %w", dir, err)
+	}
+	var classes []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".toml" || strings.HasSuffix(name, "-verlauf.toml") {
+			continue
+		}
+		classes = append(classes, strings.TrimSuffix(name, ".toml"))
+	}
+	sort.Strings(classes)
+	return classes, nil
+}
+
+// classPath gibt den Pfad der Datei einer Klasse zurück (absolut, damit readTomlConfig nicht weitersucht).
+func classPath(dir string, class string) string {
+	path := filepath.Join(dir, class+".toml")
+	if absPath, err := filepath.Abs(path); err == nil {
+		return absPath
+	}
+	return path
+}
+
+// describeClasses gibt die Klassen für Fehlermeldungen aus (z.B. '5a', '7b').
+func describeClasses(classes []string) string {
+	if len(classes) == 0 {
+		return "keine"
+	}
+	return formatNameList(classes)
+}
+
+// ############################################################################################
+// chooseClassFromMenu zeigt alle Klassen nummeriert an und fragt, welche gemischt werden soll.
+func chooseClassFromMenu(classes []string) (string, error) {
+	fmt.Println("=== Welche Klasse möchtest du mischen?")
+	for i, class := range classes {
+		fmt.Printf("  %d) %s\n", i+1, class)
+	}
+	input := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("Nummer oder Name der Klasse eingeben: ")
+		line, err := input.ReadString('\n')
+		if err == io.EOF && line == "" { // Keine Eingabe möglich (z.B. Eingabe umgeleitet).
+			return "", fmt.Errorf("❌ Keine Klasse ausgewählt, bitte mit '-klasse' angeben")
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			continue // Nur Enter gedrückt: nochmals fragen.
+		}
+		if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(classes) {
+			return classes[number-1], nil
+		}
+		for _, class := range classes {
+			if strings.EqualFold(class, answer) {
+				return class, nil
+			}
+		}
+		fmt.Printf("❗️ '%s' gibt es nicht. Bitte eine Nummer zwischen 1 und %d eingeben.\n", answer, len(classes))
+	}
+}
@@ -0,0 +1,280 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"flag"    // Zum Einlesen der Optionen eines Befehls (z.B. '-groesse 3').
+	"fmt"     // Für die Hilfe und Fehlermeldungen.
+	"os"      // Zum Prüfen, ob die Konfigurationsdatei schon existiert.
+	"runtime" // Für die Anzahl CPU-Kerne (Standard für '-worker').
+	"strings" // Zum Zusammenfügen der erlaubten Werte in Fehlermeldungen.
+	"time"    // Für das Zeitbudget ('-zeit').
+)
+
+// ############################################################################################
+// defaultAttempts ist die Anzahl der Versuche, Gruppen zu bilden (wegen Zufälligkeit),
+// 		wenn sie nicht mit '-versuche' angegeben wird.
+const defaultAttempts = 1000
+
+// ############################################################################################
+// Exit-Codes des Programms, damit es aus Skripten aufgerufen werden kann (siehe '-batch').
+const (
+	exitSuccess       = 0 // Alle Schüler wurden eingeteilt (bzw. der Befehl war erfolgreich).
+	exitPartial       = 1 // Mindestens ein Szenario hat ungruppierte Schüler.
+	exitUsage         = 2 // Falscher Befehl oder falsche Optionen.
+	exitConfigCreated = 3 // Die Konfigurationsdatei fehlte, eine Musterdatei wurde erstellt.
+	exitConfigInvalid = 4 // Die Konfigurationsdatei konnte nicht gelesen werden.
+	exitInfeasible    = 5 // Eine vollständige Einteilung ist nachweislich unmöglich.
+	exitError         = 6 // Ein anderer Fehler (z.B. beim Schreiben einer Datei).
+)
+
+// defaultCommand wird ausgeführt, wenn kein Befehl angegeben ist (z.B. bei Doppelklick).
+const defaultCommand = "mischen"
+
+// defaultConfigName ist der Name der Konfigurationsdatei, wenn '-datei' nicht angegeben ist.
+const defaultConfigName = "klasse.toml"
+
+// ############################################################################################
+// commands enthält alle Befehle mit ihrer Beschreibung für die Hilfe.
+var commands = []struct {
+	Name        string // Name des Befehls.
+	Description string // Kurze Beschreibung für die Hilfe.
+}{
+	{"mischen", "Gruppen bilden (Standard, auch ohne Befehl)"},
+	{"pruefen", "Konfiguration prüfen und mögliche Probleme anzeigen, ohne Gruppen zu bilden"},
+	{"rotation", "Rotationsplan mit mehreren Runden erstellen (mit '-runden')"},
+	{"verlauf", "gespeicherte Einteilungen anzeigen"},
+	{"symmetrisch", "fehlende Gegenrichtungen der Konflikte direkt in der Konfigurationsdatei ergänzen"},
+	{"bearbeiten", "Schülerliste und Konflikte in Menüs bearbeiten, ohne die Datei von Hand zu ändern"},
+	{"erstellen", "neue Musterdatei 'klasse.toml' erstellen (mit '-klasse' im Ordner 'klassen')"},
+}
+
+// commandAliases erlaubt auch die englischen Namen der Befehle.
+var commandAliases = map[string]string{
+	"mix":        "mischen",
+	"check":      "pruefen",
+	"history":    "verlauf",
+	"init":       "erstellen",
+	"symmetrize": "symmetrisch",
+	"edit":       "bearbeiten",
+}
+
+// outputFormats enthält die erlaubten Werte für '-format'.
+var outputFormats = []string{"text", "csv", "json", "html", "markdown", "tabelle"}
+
+// ############################################################################################
+// cliOptions enthält den Befehl und alle Optionen von der Kommandozeile.
+type cliOptions struct {
+	Command    string          // Der auszuführende Befehl (z.B. "mischen").
+	ConfigPath string          // Pfad der Konfigurationsdatei ('-datei').
+	ClassName  string          // Gewählte Klasse im Ordner 'klassen' ('-klasse', siehe selectClass).
+	GroupSize  int             // Gruppengröße ('-groesse', 0 = 2er-, 3er- und 4er-Gruppen).
+	GroupCount int             // Anzahl Gruppen ('-gruppen', 0 = Gruppengröße-Modus).
+	Rounds     int             // Anzahl Runden für einen Rotationsplan ('-runden').
+	Attempts   int             // Anzahl Versuche der Zufallssuche ('-versuche').
+	Format     string          // Ausgabeformat ('-format').
+	OutputPath string          // Datei für den Export ('-ausgabe', leer = neben der Konfiguration, "-" = stdout).
+	Title      string          // Überschrift des HTML-Aushangs ('-titel').
+	GroupNames []string        // Eigene Gruppennamen für den HTML-Aushang ('-namen', durch Kommas getrennt).
+	ExactFirst bool            // Exakte Suche zuerst verwenden ('-exakt').
+	Batch      bool            // Ohne Rückfragen laufen, z.B. für Skripte ('-batch').
+	Seed       int64           // Startwert der Zufallsquelle ('-seed', 0 = zufällig).
+	Workers    int             // Anzahl gleichzeitig suchender Worker ('-worker').
+	TimeBudget time.Duration   // Suchzeit pro Szenario statt fester Versuche ('-zeit', 0 = aus).
+	SetFlags   map[string]bool // Beim Aufruf angegebene Optionen, sie haben Vorrang vor [einstellungen].
+}
+
+// ############################################################################################
+// parseCommandLine liest den Befehl und die Optionen aus den Argumenten (ohne Programmnamen).
+// Der Befehl ist optional: 'klassenmischer -gruppen 7' entspricht 'klassenmischer mischen -gruppen 7'.
+// Ohne Argumente (z.B. bei Doppelklick) wird wie bisher 'mischen' mit allen Szenarien ausgeführt.
+func parseCommandLine(args []string) (*cliOptions, error) {
+	options := &cliOptions{Command: defaultCommand}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") { // Das erste Argument ist ein Befehl.
+		options.Command = args[0]
+		if name, ok := commandAliases[options.Command]; ok {
+			options.Command = name
+		}
+		args = args[1:]
+	}
+	if !isKnownCommand(options.Command) {
+		printUsage()
+		return nil, fmt.Errorf("❌ Unbekannter Befehl '%s'", options.Command)
+	}
+
+	flags := flag.NewFlagSet(options.Command, flag.ContinueOnError)
+	flags.Usage = printUsage
+	flags.StringVar(&options.ConfigPath, "datei", defaultConfigName, "Pfad der Konfigurationsdatei oder eines Ordners mit mehreren Klassen")
+	// Mit '-klasse 7b' wird die Datei '7b.toml' im Ordner 'klassen' verwendet.
+	flags.StringVar(&options.ClassName, "klasse", "", "Name der Klasse im Ordner 'klassen' (ohne Angabe: Auswahlmenü)")
+	// Optional: Anzahl Gruppen statt Gruppengröße (z.B. 'klassenmischer -gruppen 7').
+	flags.IntVar(&options.GroupCount, "gruppen", 0, "Anzahl Gruppen, auf die die Klasse möglichst gleichmäßig verteilt wird")
+	// Mit '-groesse' wird nur ein Szenario mit dieser Gruppengröße berechnet (z.B. 5er- oder 6er-Gruppen).
+	flags.IntVar(&options.GroupSize, "groesse", 0, "Gruppengröße; ohne Angabe werden 2er-, 3er- und 4er-Gruppen berechnet")
+	// Mit '-runden' wird ein Rotationsplan mit so vielen Einteilungen erstellt.
+	flags.IntVar(&options.Rounds, "runden", 0, "Anzahl Runden für einen Rotationsplan, in dem möglichst niemand zweimal zusammenarbeitet")
+	flags.IntVar(&options.Attempts, "versuche", defaultAttempts, "Anzahl Versuche der Zufallssuche pro Szenario")
+	flags.StringVar(&options.Format, "format", "text", "Ausgabeformat: "+strings.Join(outputFormats, ", "))
+	// Mit '-ausgabe -' wird der Export nach stdout geschrieben (z.B. 'klassenmischer -batch -format csv -ausgabe - > gruppen.csv').
+	flags.StringVar(&options.OutputPath, "ausgabe", "", "Datei für den Export (Standard: neben der Konfigurationsdatei, '-' = stdout)")
+	// Mit '-exakt' wird zuerst vollständig gesucht (Backtracking) statt zufällig.
+	flags.BoolVar(&options.ExactFirst, "exakt", false, "exakte Suche verwenden, die beweist, ob eine vollständige Einteilung möglich ist")
+	// Mit '-batch' wartet das Programm nie auf Eingaben und meldet das Ergebnis über den Exit-Code.
+	flags.BoolVar(&options.Batch, "batch", false, "ohne Rückfragen laufen und das Ergebnis als Exit-Code melden")
+	// Für den HTML-Aushang: Überschrift und eigene Gruppennamen (z.B. '-namen "Löwen,Tiger,Bären"').
+	flags.StringVar(&options.Title, "titel", "Gruppeneinteilung", "Überschrift des HTML-Aushangs bzw. der Tabellen")
+	groupNames := flags.String("namen", "", "eigene Gruppennamen für den HTML-Aushang und die Tabellen, durch Kommas getrennt")
+	// Mit '-seed' lässt sich eine frühere Einteilung genau wiederholen.
+	flags.Int64Var(&options.Seed, "seed", 0, "Startwert der Zufallsquelle, um eine Einteilung zu wiederholen (0 = zufällig)")
+	flags.IntVar(&options.Workers, "worker", runtime.NumCPU(), "Anzahl gleichzeitig suchender Worker (Standard: Anzahl CPU-Kerne)")
+	// Mit '-zeit 3s' wird pro Szenario 3 Sekunden lang gesucht statt einer festen Anzahl Versuche.
+	flags.DurationVar(&options.TimeBudget, "zeit", 0, "Suchzeit pro Szenario statt fester Versuche (z.B. 3s)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err // Die Fehlermeldung hat das flag-Paket bereits ausgegeben.
+	}
+	options.SetFlags = make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { options.SetFlags[f.Name] = true })
+	for _, name := range strings.Split(*groupNames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			options.GroupNames = append(options.GroupNames, name)
+		}
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("❌ Unerwartete Argumente: %s", strings.Join(flags.Args(), " "))
+	}
+
+	// Werte prüfen, bevor etwas berechnet wird.
+	if options.GroupSize < 0 || options.GroupCount < 0 || options.Rounds < 0 {
+		return nil, fmt.Errorf("❌ '-groesse', '-gruppen' und '-runden' dürfen nicht negativ sein")
+	}
+	if options.GroupSize > 0 && options.GroupCount > 0 { // Sonst gälte stillschweigend nur eine der beiden Angaben.
+		return nil, fmt.Errorf("❌ '-groesse' und '-gruppen' können nicht gleichzeitig verwendet werden. Bitte gib entweder die Gruppengröße oder die Anzahl Gruppen an")
+	}
+	if options.Seed < 0 {
+		return nil, fmt.Errorf("❌ '-seed' darf nicht negativ sein")
+	}
+	if options.Workers < 1 {
+		return nil, fmt.Errorf("❌ '-worker' muss mindestens 1 sein, gefunden: %d", options.Workers)
+	}
+	if options.TimeBudget < 0 {
+		return nil, fmt.Errorf("❌ '-zeit' darf nicht negativ sein, gefunden: %v", options.TimeBudget)
+	}
+	if options.Attempts < 1 {
+		return nil, fmt.Errorf("❌ '-versuche' muss mindestens 1 sein, gefunden: %d", options.Attempts)
+	}
+	if !isOutputFormat(options.Format) {
+		return nil, fmt.Errorf("❌ Unbekanntes Ausgabeformat '%s', erlaubt sind: %s", options.Format, strings.Join(outputFormats, ", "))
+	}
+	if options.Command == "rotation" && options.Rounds == 0 {
+		return nil, fmt.Errorf("❌ Für 'rotation' fehlt die Anzahl Runden (z.B. 'rotation -runden 6 -groesse 3')")
+	}
+	if options.Command == "bearbeiten" && options.Batch {
+		return nil, fmt.Errorf("❌ 'bearbeiten' braucht Eingaben im Terminal und kann nicht mit '-batch' verwendet werden")
+	}
+	if options.Command == "mischen" && options.Rounds > 0 { // Wie bisher: '-runden' erstellt einen Rotationsplan.
+		options.Command = "rotation"
+	}
+	return options, nil
+}
+
+// isKnownCommand prüft, ob es einen Befehl mit diesem Namen gibt.
+func isKnownCommand(name string) bool {
+	for _, command := range commands {
+		if command.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isOutputFormat prüft, ob '-format' einen erlaubten Wert hat.
+func isOutputFormat(format string) bool {
+	for _, allowed := range outputFormats {
+		if allowed == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ############################################################################################
+// printUsage gibt eine kurze Hilfe mit allen Befehlen und Optionen aus.
+func printUsage() {
+	fmt.Println("Aufruf: klassenmischer [Befehl] [Optionen]")
+	fmt.Println("\nBefehle:")
+	for _, command := range commands {
+		fmt.Printf("  %-12s %s\n", command.Name, command.Description)
+	}
+	fmt.Println("\nOptionen:")
+	fmt.Println("  -datei PFAD      Konfigurationsdatei oder Ordner mit Klassen (Standard: klasse.toml)")
+	fmt.Println("  -klasse NAME     Klasse aus dem Ordner 'klassen' wählen (z.B. 7b für klassen/7b.toml)")
+	fmt.Println("  -groesse N       nur Gruppen mit N Personen berechnen")
+	fmt.Println("  -gruppen N       Klasse auf genau N Gruppen verteilen")
+	fmt.Println("  -runden N        Rotationsplan mit N Runden erstellen")
+	fmt.Printf("  -versuche N      Versuche der Zufallssuche pro Szenario (Standard: %d)\n", defaultAttempts)
+	fmt.Println("  -zeit DAUER      statt fester Versuche so lange suchen (z.B. 3s)")
+	fmt.Printf("  -worker N        Anzahl gleichzeitig suchender Worker (Standard: %d CPU-Kerne)\n", runtime.NumCPU())
+	fmt.Printf("  -format F        Ausgabeformat (%s)\n", strings.Join(outputFormats, ", "))
+	fmt.Println("  -ausgabe PFAD    Datei für den Export ('-' = stdout, Standard: neben der Konfigurationsdatei)")
+	fmt.Println("  -titel TEXT      Überschrift des HTML-Aushangs")
+	fmt.Println("  -namen A,B,...   eigene Gruppennamen für den HTML-Aushang")
+	fmt.Println("  -exakt           exakte Suche zuerst verwenden")
+	fmt.Println("  -seed N          Einteilung mit dem Seed N wiederholen (wird bei jedem Ergebnis angezeigt)")
+	fmt.Println("  -batch           ohne Rückfragen laufen (für Skripte)")
+	fmt.Println("\nExit-Codes mit -batch: 0 = alle eingeteilt, 1 = Schüler ungruppiert, 2 = falsche Optionen,")
+	fmt.Println("                       3 = Musterdatei erstellt, 4 = Konfiguration fehlerhaft, 5 = Einteilung unmöglich, 6 = anderer Fehler")
+	fmt.Println("\nOhne Befehl und Optionen (z.B. bei Doppelklick) werden 2er-, 3er- und 4er-Gruppen gebildet.")
+}
+
+// ############################################################################################
+// exitCodeFor bestimmt den Exit-Code aus den Ergebnissen aller Szenarien bzw. Runden.
+// Eine nachweislich unmögliche Einteilung wiegt schwerer als ungruppierte Schüler.
+func exitCodeFor(results []scenarioResult) int {
+	code := exitSuccess
+	for _, result := range results {
+		if result.Infeasible {
+			return exitInfeasible
+		}
+		if len(result.Ungrouped) > 0 {
+			code = exitPartial
+		}
+	}
+	return code
+}
+
+// ############################################################################################
+// runInitCommand erstellt für den Befehl 'erstellen' eine neue Musterdatei.
+// Eine bestehende Datei wird nie überschrieben.
+func runInitCommand(options *cliOptions) error {
+	if _, err := os.Stat(options.ConfigPath); err == nil {
+		return fmt.Errorf("❌ '%s' existiert bereits und wird nicht überschrieben", options.ConfigPath)
+	}
+	if err := writeSampleConfig(options.ConfigPath); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Musterdatei erstellt: %s\n", options.ConfigPath)
+	return nil
+}
+
+// ############################################################################################
+// runHistoryCommand zeigt für den Befehl 'verlauf' alle gespeicherten Einteilungen an.
+func runHistoryCommand(config *Config) error {
+	historyPath := historyPathFor(config.Path)
+	history, err := loadHistory(historyPath)
+	if err != nil {
+		return err
+	}
+	if len(history.Sessions) == 0 {
+		fmt.Printf("ℹ️ Noch keine Einteilung gespeichert (%s).\n", historyPath)
+		return nil
+	}
+
+	fmt.Printf("=== Verlauf: %d gespeicherte Einteilungen in %s\n", len(history.Sessions), historyPath)
+	fmt.Printf("Die letzten %d werden bei neuen Einteilungen berücksichtigt.\n", recentHistorySessions)
+	for i, session := range history.Sessions {
+		fmt.Printf("\n--- %d. %s, %s\n", i+1, session.Date, session.Scenario)
+		for j, group := range session.Groups {
+			fmt.Printf("Gruppe %d (%d Personen): %v\n", j+1, len(group), group)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main // Tests für die Kommandozeile in cli.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"testing" // Test-Framework von Go.
+)
+
+// ############################################################################################
+// TestExitCodeFor prüft den Exit-Code für '-batch' aus den Ergebnissen aller Szenarien.
+func TestExitCodeFor(t *testing.T) {
+	complete := scenarioResult{Groups: [][]string{{"Anna", "Ben"}}}
+	partial := scenarioResult{Groups: [][]string{{"Anna", "Ben"}}, Ungrouped: []string{"Carla"}}
+	infeasible := scenarioResult{Ungrouped: []string{"Anna", "Ben"}, Infeasible: true}
+
+	tests := []struct {
+		name    string
+		results []scenarioResult
+		want    int
+	}{
+		{"keine Ergebnisse", nil, exitSuccess},
+		{"alle eingeteilt", []scenarioResult{complete, complete}, exitSuccess},
+		{"Schüler ungruppiert", []scenarioResult{complete, partial}, exitPartial},
+		{"unmöglich", []scenarioResult{infeasible}, exitInfeasible},
+		{"unmöglich wiegt schwerer als ungruppiert", []scenarioResult{partial, infeasible, complete}, exitInfeasible},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := exitCodeFor(test.results); got != test.want {
+				t.Errorf("exitCodeFor = %d, erwartet %d", got, test.want)
+			}
+		})
+	}
+}
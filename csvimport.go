@@ -0,0 +1,311 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"encoding/csv"  // Zum Lesen der Klassenliste aus der Schulverwaltung.
+	"fmt"           // Zum Formatieren der Fehlermeldungen.
+	"os"            // Zum Lesen der CSV-Datei.
+	"path/filepath" // Relative Pfade gelten ab dem Ordner von 'klasse.toml'.
+	"sort"          // Für eine stabile Reihenfolge der Merkmale.
+	"strings"       // Zum Vergleichen der Spaltenüberschriften.
+	"unicode/utf8"  // Zum Erkennen von Dateien, die nicht in UTF-8 gespeichert sind.
+
+	"github.com/pelletier/go-toml" // Zum Lesen des Abschnitts [import].
+)
+
+// ############################################################################################
+// importSection ist der Name des Abschnitts, mit dem die Schülerliste aus einer CSV-Datei gelesen wird.
+// Beispiel für eine Klassenliste aus der Schulverwaltung (Nachname; Vorname; Klasse; Geschlecht):
+// 		[import]
+// 		datei = "klasse7b.csv"                     # relativ zum Ordner von klasse.toml
+// 		trennzeichen = ";"                         # optional, sonst automatisch erkannt
+// 		name = ["Vorname", "Nachname"]             # Spalten, die zusammen den Namen ergeben
+// 		merkmale = { geschlecht = "Geschlecht" }   # Merkmal = Spalte
+// Spalten werden über ihre Überschrift (ohne Groß-/Kleinschreibung) oder ihre Nummer (ab 1) angegeben.
+const importSection = "import"
+
+// importKeys sind die erlaubten Schlüssel im Abschnitt [import].
+var importKeys = []string{"datei", "trennzeichen", "kopfzeile", "name", "merkmale"}
+
+// importDelimiters sind die Trennzeichen, die automatisch erkannt werden.
+var importDelimiters = []rune{';', ',', '\t'}
+
+// ############################################################################################
+// csvImport beschreibt, wie die Spalten der CSV-Datei auf Namen und Merkmale abgebildet werden.
+type csvImport struct {
+	Path       string                 // Pfad der CSV-Datei.
+	Delimiter  rune                   // Trennzeichen (0 = automatisch erkennen).
+	Header     bool                   // Enthält die erste Zeile die Spaltenüberschriften (Standard: ja)?
+	NameCols   []interface{}          // Spalten für den Namen (Überschrift oder Nummer), leer = automatisch.
+	Attributes map[string]interface{} // Merkmal → Spalte, nil = alle übrigen Spalten.
+}
+
+// ############################################################################################
+// readImportSection liest den Abschnitt [import] und die darin angegebene CSV-Datei.
+// Relative Pfade werden ab dem Ordner der Konfigurationsdatei 'configPath' gesucht.
+// Gibt 'false' zurück, wenn es keinen Abschnitt [import] gibt. Unbekannte Schlüssel, falsche Typen und
+// 		unbrauchbare Zeilen der CSV-Datei kommen in den Prüfbericht. Ein Fehler wird nur zurückgegeben,
+// 		wenn gar keine Schülerliste gelesen werden kann (z.B. ohne 'datei').
+func readImportSection(tree *toml.Tree, configPath string, report *validationReport) ([]string, map[string]map[string]string, bool, error) {
+	section := tree.Get(importSection)
+	if section == nil {
+		return nil, nil, false, nil // Abschnitt ist optional.
+	}
+	table, ok := section.(*toml.Tree)
+	if !ok {
+		report.add(tree.GetPositionPath([]string{importSection}), issueWrongType, "'%s' muss ein Abschnitt [%s] sein, gefunden: %s", importSection, importSection, describeType(section))
+		return nil, nil, false, nil
+	}
+	where := fmt.Sprintf("[%s]", importSection)
+	position := func(key string) toml.Position { // Fundstelle eines Schlüssels, sonst die Kopfzeile.
+		if position := table.GetPositionPath([]string{key}); !position.Invalid() {
+			return position
+		}
+		return table.Position()
+	}
+
+	checkKnownKeys(table, importKeys, where, report)
+
+	settings := csvImport{Header: true}
+	switch path := table.Get("datei").(type) {
+	case nil:
+		return nil, nil, true, fmt.Errorf("im Abschnitt [%s] fehlt 'datei'", importSection)
+	case string:
+		settings.Path = path
+	default:
+		return nil, nil, true, fmt.Errorf("'datei' im Abschnitt [%s] muss ein Dateiname in Anführungszeichen sein, gefunden: %s", importSection, describeType(path))
+	}
+	if settings.Path == "" {
+		return nil, nil, true, fmt.Errorf("im Abschnitt [%s] ist 'datei' leer", importSection)
+	}
+	if !filepath.IsAbs(settings.Path) {
+		settings.Path = filepath.Join(filepath.Dir(configPath), settings.Path)
+	}
+	switch delimiter := table.Get("trennzeichen").(type) {
+	case nil, string:
+		text, _ := delimiter.(string)
+		if text == "\\t" || strings.EqualFold(text, "tab") { // Tabulator auch ohne Escape-Sequenz.
+			text = "\t"
+		}
+		if text != "" && utf8.RuneCountInString(text) != 1 {
+			report.add(position("trennzeichen"), issueWrongType, "%s: 'trennzeichen' muss genau ein Zeichen sein, gefunden: %q. Das Trennzeichen wird automatisch erkannt", where, text)
+		} else if text != "" {
+			settings.Delimiter, _ = utf8.DecodeRuneInString(text)
+		}
+	default:
+		report.add(position("trennzeichen"), issueWrongType, "%s: 'trennzeichen' muss ein Zeichen in Anführungszeichen sein, gefunden: %s. Das Trennzeichen wird automatisch erkannt", where, describeType(delimiter))
+	}
+	switch header := table.Get("kopfzeile").(type) {
+	case nil: // Standard: mit Kopfzeile.
+	case bool:
+		settings.Header = header
+	default:
+		report.add(position("kopfzeile"), issueWrongType, "%s: 'kopfzeile' muss true oder false sein, gefunden: %s", where, describeType(header))
+	}
+	switch name := table.Get("name").(type) {
+	case nil: // Automatisch erkennen.
+	case []interface{}:
+		settings.NameCols = name
+	default:
+		settings.NameCols = []interface{}{name}
+	}
+	if mapping := table.Get("merkmale"); mapping != nil {
+		if mappingTree, isTable := mapping.(*toml.Tree); isTable {
+			settings.Attributes = mappingTree.ToMap()
+		} else {
+			report.add(position("merkmale"), issueWrongType, "%s: 'merkmale' muss eine Tabelle sein, z.B. { geschlecht = \"Geschlecht\" }, gefunden: %s. Es werden alle übrigen Spalten verwendet", where, describeType(mapping))
+		}
+	}
+
+	names, attributes, err := readStudentCSV(settings, position("datei"), report)
+	return names, attributes, true, err
+}
+
+// ############################################################################################
+// readStudentCSV liest die Schülerliste samt Merkmalen aus einer CSV-Datei.
+// Dateien aus Excel werden oft nicht in UTF-8 gespeichert: Sie werden dann als Windows-1252 gelesen,
+// 		damit Umlaute erhalten bleiben. Leere Zeilen werden übersprungen.
+// Zeilen ohne Namen und doppelte Namen kommen mit Zeile und Spalte der CSV-Datei in den Prüfbericht,
+// 		an der Fundstelle 'position' von 'datei' in der Konfigurationsdatei.
+func readStudentCSV(settings csvImport, position toml.Position, report *validationReport) ([]string, map[string]map[string]string, error) {
+	data, err := os.ReadFile(settings.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Fehler beim Lesen der Klassenliste '%s': %w", settings.Path, err)
+	}
+	text := strings.TrimPrefix(decodeCSVText(data), "\ufeff") // Byte Order Mark von Excel entfernen.
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = settings.Delimiter
+	if reader.Comma == 0 {
+		reader.Comma = detectDelimiter(text)
+	}
+	reader.FieldsPerRecord = -1 // Unterschiedlich lange Zeilen erlauben.
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Fehler beim Lesen der Klassenliste '%s': %w", settings.Path, err)
+	}
+
+	var header []string
+	firstRow := 1 // Zeilennummer der ersten Datenzeile (für Warnungen).
+	if settings.Header && len(records) > 0 {
+		header = records[0]
+		records = records[1:]
+		firstRow = 2
+	}
+
+	// Spalten für den Namen bestimmen.
+	nameSpecs := settings.NameCols
+	if len(nameSpecs) == 0 {
+		if findColumn(header, "vorname") >= 0 && findColumn(header, "nachname") >= 0 {
+			nameSpecs = []interface{}{"Vorname", "Nachname"}
+		} else {
+			nameSpecs = []interface{}{"Name"}
+		}
+	}
+	var nameCols []int
+	for _, spec := range nameSpecs {
+		col, err := resolveColumn(header, spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Name in '%s': %w", settings.Path, err)
+		}
+		nameCols = append(nameCols, col)
+	}
+
+	// Spalten für die Merkmale bestimmen: entweder wie angegeben oder alle übrigen Spalten.
+	attributeCols := make(map[string]int)
+	if settings.Attributes != nil {
+		for attribute, spec := range settings.Attributes {
+			col, err := resolveColumn(header, spec)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Merkmal '%s' in '%s': %w", attribute, settings.Path, err)
+			}
+			attributeCols[attribute] = col
+		}
+	} else {
+		isNameCol := make(map[int]bool)
+		for _, col := range nameCols {
+			isNameCol[col] = true
+		}
+		for col, title := range header {
+			key := strings.ToLower(strings.TrimSpace(title))
+			if !isNameCol[col] && key != "" {
+				attributeCols[key] = col
+			}
+		}
+	}
+	attributeNames := make([]string, 0, len(attributeCols))
+	for attribute := range attributeCols {
+		attributeNames = append(attributeNames, attribute)
+	}
+	sort.Strings(attributeNames)
+
+	var names []string
+	attributes := make(map[string]map[string]string)
+	for i, record := range records {
+		var parts []string
+		for _, col := range nameCols {
+			if value := cell(record, col); value != "" {
+				parts = append(parts, value)
+			}
+		}
+		name := strings.Join(parts, " ")
+		where := fmt.Sprintf("'%s', Zeile %d, Spalte %d", filepath.Base(settings.Path), firstRow+i, nameCols[0]+1)
+		if name == "" {
+			if strings.TrimSpace(strings.Join(record, "")) != "" { // Nur nicht leere Zeilen melden.
+				report.add(position, issueEmptyName, "%s: Die Zeile hat keinen Namen und wird ignoriert", where)
+			}
+			continue
+		}
+		if _, exists := attributes[name]; exists {
+			report.add(position, issueDuplicate, "%s: '%s' steht mehrfach in der Klassenliste und wird nur einmal übernommen", where, name)
+			continue
+		}
+		names = append(names, name)
+		studentAttributes := make(map[string]string)
+		for _, attribute := range attributeNames {
+			if value := cell(record, attributeCols[attribute]); value != "" {
+				studentAttributes[attribute] = value
+			}
+		}
+		attributes[name] = studentAttributes
+	}
+	return names, attributes, nil
+}
+
+// ############################################################################################
+// decodeCSVText gibt den Inhalt als Text zurück. Ist er kein gültiges UTF-8,
+// 		wird er als Windows-1252 gelesen, wie es Excel unter Windows speichert.
+func decodeCSVText(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	var sb strings.Builder
+	for _, b := range data {
+		if b >= 0x80 && b <= 0x9f {
+			sb.WriteRune(windows1252[b-0x80])
+		} else {
+			sb.WriteRune(rune(b)) // Alle anderen Bytes sind wie in ISO-8859-1 (Latin-1).
+		}
+	}
+	return sb.String()
+}
+
+// windows1252 enthält die Zeichen für die Bytes 0x80 bis 0x9F, in denen Windows-1252 von ISO-8859-1 abweicht
+// 		(z.B. typografische Anführungszeichen, Gedankenstrich und €). Die fünf freien Bytes bleiben Steuerzeichen.
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+// detectDelimiter wählt das Trennzeichen, das in der ersten Zeile am häufigsten vorkommt.
+func detectDelimiter(text string) rune {
+	firstLine, _, _ := strings.Cut(text, "\n")
+	best, bestCount := importDelimiters[0], 0 // Ohne Treffer: Semikolon wie in deutschen Excel-Exporten.
+	for _, delimiter := range importDelimiters {
+		if count := strings.Count(firstLine, string(delimiter)); count > bestCount {
+			best, bestCount = delimiter, count
+		}
+	}
+	return best
+}
+
+// resolveColumn gibt den Index der Spalte zurück, die über ihre Überschrift oder Nummer (ab 1) angegeben ist.
+func resolveColumn(header []string, spec interface{}) (int, error) {
+	switch column := spec.(type) {
+	case int64:
+		if column < 1 {
+			return -1, fmt.Errorf("Spaltennummern beginnen bei 1, gefunden: %d", column)
+		}
+		return int(column - 1), nil
+	case string:
+		if header == nil {
+			return -1, fmt.Errorf("Spalte '%s' kann ohne Kopfzeile nicht gefunden werden, gib die Spaltennummer an", column)
+		}
+		if col := findColumn(header, column); col >= 0 {
+			return col, nil
+		}
+		return -1, fmt.Errorf("Spalte '%s' nicht gefunden. Vorhandene Spalten: %s", column, strings.Join(header, ", "))
+	default:
+		return -1, fmt.Errorf("Spalte muss als Überschrift oder Nummer angegeben werden, gefunden: %v", spec)
+	}
+}
+
+// findColumn sucht eine Spaltenüberschrift ohne Beachtung von Groß-/Kleinschreibung (-1 = nicht gefunden).
+func findColumn(header []string, title string) int {
+	for col, candidate := range header {
+		if strings.EqualFold(strings.TrimSpace(candidate), strings.TrimSpace(title)) {
+			return col
+		}
+	}
+	return -1
+}
+
+// cell gibt den bereinigten Inhalt einer Zelle zurück (leer, wenn die Zeile zu kurz ist).
+func cell(record []string, col int) string {
+	if col < 0 || col >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[col])
+}
@@ -0,0 +1,192 @@
+package main // Tests für den Import der Schülerliste in csvimport.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"os"            // Zum Schreiben der CSV-Dateien für die Tests.
+	"path/filepath" // Für die Pfade im temporären Ordner.
+	"reflect"       // Zum Vergleichen der Namen und Merkmale.
+	"testing"       // Test-Framework von Go.
+
+	"github.com/pelletier/go-toml" // Für die Fundstelle im Prüfbericht.
+)
+
+// ############################################################################################
+// TestDetectDelimiter prüft die Erkennung des Trennzeichens an der ersten Zeile.
+func TestDetectDelimiter(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want rune
+	}{
+		{"Semikolon", "Vorname;Nachname;Klasse\nAnna;Muster;5b\n", ';'},
+		{"Komma", "Vorname,Nachname,Klasse\nAnna,Muster,5b\n", ','},
+		{"Tabulator", "Vorname\tNachname\nAnna\tMuster\n", '\t'},
+		{"nur die erste Zeile zählt", "Name;Klasse\nMuster, Anna,5b,x,y\n", ';'},
+		{"ohne Trennzeichen", "Name\nAnna\n", ';'},
+		{"leer", "", ';'},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := detectDelimiter(test.text); got != test.want {
+				t.Errorf("detectDelimiter = %q, erwartet %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestResolveColumn prüft, wie Spalten über Überschrift oder Nummer gefunden werden.
+func TestResolveColumn(t *testing.T) {
+	header := []string{"Vorname", " Nachname ", "Geschlecht"}
+	tests := []struct {
+		name    string
+		header  []string
+		spec    interface{}
+		want    int
+		wantErr bool
+	}{
+		{"Überschrift", header, "Geschlecht", 2, false},
+		{"ohne Groß-/Kleinschreibung und Leerzeichen", header, "nachname", 1, false},
+		{"Nummer ab 1", header, int64(1), 0, false},
+		{"Nummer ohne Kopfzeile", nil, int64(3), 2, false},
+		{"Nummer 0", header, int64(0), -1, true},
+		{"unbekannte Überschrift", header, "Niveau", -1, true},
+		{"Überschrift ohne Kopfzeile", nil, "Vorname", -1, true},
+		{"falscher Typ", header, true, -1, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := resolveColumn(test.header, test.spec)
+			if got != test.want || (err != nil) != test.wantErr {
+				t.Errorf("resolveColumn(%v) = %d, %v; erwartet %d, Fehler: %v", test.spec, got, err, test.want, test.wantErr)
+			}
+		})
+	}
+}
+
+// TestDecodeCSVText prüft, dass Dateien ohne gültiges UTF-8 als Windows-1252 gelesen werden.
+func TestDecodeCSVText(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"UTF-8 bleibt", "Jürgen „Jo“", "Jürgen „Jo“"},
+		{"Umlaute wie Latin-1", "J\xfcrgen \xd6zt\xfcrk", "Jürgen Öztürk"},
+		{"Anführungszeichen und Strich", "\x84Jo\x93 \x96 \x91Mo\x92", "„Jo“ – ‘Mo’"},
+		{"übrige Zeichen aus 0x80 bis 0x9F", "\x80\x8a\x8c\x8e\x9a\x9c\x9e\x9f\x85\x99", "€ŠŒŽšœžŸ…™"},
+		{"freie Bytes bleiben Steuerzeichen", "A\x81\x8dB", "A\u0081\u008dB"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := decodeCSVText([]byte(test.data)); got != test.want {
+				t.Errorf("decodeCSVText(%q) = %q, erwartet %q", test.data, got, test.want)
+			}
+		})
+	}
+}
+
+// ############################################################################################
+// TestReadStudentCSV prüft die Zuordnung der Spalten zu Namen und Merkmalen.
+func TestReadStudentCSV(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		settings       csvImport // 'Path' wird vom Test gesetzt.
+		wantNames      []string
+		wantAttributes map[string]map[string]string
+		wantIssues     int
+	}{
+		{
+			name:      "Vorname und Nachname automatisch, übrige Spalten als Merkmale",
+			content:   "Vorname;Nachname;Geschlecht\nAnna;Muster;w\nBen;Beispiel;m\n",
+			settings:  csvImport{Header: true},
+			wantNames: []string{"Anna Muster", "Ben Beispiel"},
+			wantAttributes: map[string]map[string]string{
+				"Anna Muster":  {"geschlecht": "w"},
+				"Ben Beispiel": {"geschlecht": "m"},
+			},
+		},
+		{
+			name:      "Spalte 'Name' automatisch, Komma erkannt",
+			content:   "Name,Niveau\nAnna,stark\nBen,\n",
+			settings:  csvImport{Header: true},
+			wantNames: []string{"Anna", "Ben"},
+			wantAttributes: map[string]map[string]string{
+				"Anna": {"niveau": "stark"},
+				"Ben":  {},
+			},
+		},
+		{
+			name:      "Spaltennummern ohne Kopfzeile",
+			content:   "5b;Anna\n5b;Ben\n",
+			settings:  csvImport{Header: false, NameCols: []interface{}{int64(2)}, Attributes: map[string]interface{}{"klasse": int64(1)}},
+			wantNames: []string{"Anna", "Ben"},
+			wantAttributes: map[string]map[string]string{
+				"Anna": {"klasse": "5b"},
+				"Ben":  {"klasse": "5b"},
+			},
+		},
+		{
+			name:      "nur die angegebenen Merkmale",
+			content:   "Name;Geschlecht;Religion\nAnna;w;ev\n",
+			settings:  csvImport{Header: true, Attributes: map[string]interface{}{"geschlecht": "Geschlecht"}},
+			wantNames: []string{"Anna"},
+			wantAttributes: map[string]map[string]string{
+				"Anna": {"geschlecht": "w"},
+			},
+		},
+		{
+			name:      "leere und doppelte Namen im Prüfbericht",
+			content:   "Name;Niveau\nAnna;stark\n;mittel\n\nAnna;schwach\n",
+			settings:  csvImport{Header: true},
+			wantNames: []string{"Anna"},
+			wantAttributes: map[string]map[string]string{
+				"Anna": {"niveau": "stark"},
+			},
+			wantIssues: 2,
+		},
+		{
+			name:      "Windows-1252 aus Excel",
+			content:   "Name\nJ\xfcrgen\n",
+			settings:  csvImport{Header: true},
+			wantNames: []string{"Jürgen"},
+			wantAttributes: map[string]map[string]string{
+				"Jürgen": {},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			settings := test.settings
+			settings.Path = filepath.Join(t.TempDir(), "klassenliste.csv")
+			if err := os.WriteFile(settings.Path, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			report := newValidationReport("klasse.toml")
+			names, attributes, err := readStudentCSV(settings, toml.Position{Line: 1, Col: 1}, report)
+			if err != nil {
+				t.Fatalf("unerwarteter Fehler: %v", err)
+			}
+			if !reflect.DeepEqual(names, test.wantNames) {
+				t.Errorf("Namen = %v, erwartet %v", names, test.wantNames)
+			}
+			if !reflect.DeepEqual(attributes, test.wantAttributes) {
+				t.Errorf("Merkmale = %v, erwartet %v", attributes, test.wantAttributes)
+			}
+			if len(report.Issues) != test.wantIssues {
+				t.Errorf("%d Probleme im Prüfbericht, erwartet %d: %v", len(report.Issues), test.wantIssues, report.Issues)
+			}
+		})
+	}
+}
+
+// TestReadStudentCSVMissingColumn prüft, dass eine fehlende Namensspalte ein Fehler ist.
+func TestReadStudentCSVMissingColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "klassenliste.csv")
+	if err := os.WriteFile(path, []byte("Schüler;Klasse\nAnna;5b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := readStudentCSV(csvImport{Path: path, Header: true}, toml.Position{}, nil); err == nil {
+		t.Error("Fehler erwartet, weil es keine Spalte 'Name' gibt")
+	}
+}
@@ -0,0 +1,242 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"     // Zum Formatieren der Hinweise.
+	"sort"    // Für eine stabile Reihenfolge der Namen in den Hinweisen.
+	"strings" // Zum Zusammenfügen von Namenslisten.
+)
+
+// ############################################################################################
+// maxReportedCliques begrenzt, wie viele Konfliktgruppen bei der Analyse untersucht werden.
+// Bei normalen Klassen wird diese Grenze nie erreicht.
+const maxReportedCliques = 10000
+
+// ############################################################################################
+// diagnoseScenario sucht nach offensichtlichen Gründen, warum keine vollständige Einteilung
+// 		nach dem Szenario möglich ist, und gibt sie als konkrete Hinweise zurück.
+// Geprüft werden:
+// 1. Überbeschränkte Schüler: Für sie gibt es keine gültige Gruppe in der Mindestgröße.
+// 2. Konfliktgruppen: Schüler, die sich alle gegenseitig ausschließen, brauchen je eine eigene Gruppe.
+// 		Gibt es dafür zu wenige Gruppen oder zu wenige passende Mitschüler, ist die Einteilung unmöglich.
+// 3. Pflichtpartner: Einheiten, die größer als die größte erlaubte Gruppe sind.
+// 4. Ausgleichsregeln: Mehr Schüler mit einem begrenzten Merkmal, als die Gruppen aufnehmen können.
+// Eine leere Liste bedeutet nicht, dass eine Einteilung sicher möglich ist (siehe solveExact).
+func diagnoseScenario(allStudents []string, scenario groupScenario, rules *groupRules) []string {
+	var hints []string
+	studentCount := len(allStudents)
+	if studentCount == 0 {
+		return hints
+	}
+
+	// Mindest- und Höchstgröße sowie höchstmögliche Anzahl Gruppen für dieses Szenario bestimmen.
+	var minSize, maxSize, maxGroups int
+	if scenario.GroupCount > 0 {
+		maxGroups = scenario.GroupCount
+		if maxGroups > studentCount {
+			maxGroups = studentCount
+		}
+		sizes := groupSizesForCount(studentCount, maxGroups)
+		minSize = sizes[len(sizes)-1] // Die letzten Gruppen sind die kleinsten.
+		maxSize = sizes[0]
+	} else {
+		minSize = scenario.MinSize
+		if minSize < 1 || minSize > scenario.TargetSize { // Gleiche Korrektur wie in formGroups.
+			minSize = scenario.TargetSize
+		}
+		maxSize = scenario.MaxSize
+		if maxSize < scenario.TargetSize {
+			maxSize = scenario.TargetSize
+		}
+		maxGroups = studentCount / minSize
+	}
+
+	// Für jeden Schüler: Mit wem verträgt er sich?
+	compatible := make(map[string][]string)
+	for _, a := range allStudents {
+		for _, b := range allStudents {
+			if a != b && !rules.conflict(a, b) {
+				compatible[a] = append(compatible[a], b)
+			}
+		}
+	}
+
+	// 1. Überbeschränkte Schüler.
+	for _, student := range allStudents {
+		partners := compatible[student]
+		if len(partners) < minSize-1 {
+			hints = append(hints, fmt.Sprintf(
+				"'%s' verträgt sich nur mit %d von %d Mitschülern (%s). Für eine Gruppe mit mindestens %d Personen braucht '%s' mindestens %d passende Partner. Tipp: Entferne Konflikte von '%s' oder erlaube kleinere Gruppen.",
+				student, len(partners), studentCount-1, formatNameList(partners), minSize, student, minSize-1, student))
+		} else if len(rules.unitOf(student)) <= maxSize && !hasValidGroupWith(student, partners, minSize, maxSize, rules) { // Zu große Einheiten meldet Punkt 3.
+			hints = append(hints, fmt.Sprintf(
+				"'%s' verträgt sich mit %s, aber diese Mitschüler lassen sich nicht zu einer gültigen Gruppe mit mindestens %d Personen für '%s' zusammenstellen (Konflikte untereinander, Pflichtpartner oder Ausgleichsregeln). Tipp: Entferne einen dieser Konflikte oder Pflichtpartner oder erlaube kleinere Gruppen.",
+				student, formatNameList(partners), minSize, student))
+		}
+	}
+
+	// 2. Konfliktgruppen: Schüler, die sich alle gegenseitig ausschließen.
+	for _, clique := range conflictCliques(allStudents, compatible) {
+		if len(clique) > maxGroups {
+			hints = append(hints, fmt.Sprintf(
+				"%s schließen sich alle gegenseitig aus und brauchen je eine eigene Gruppe. Es gibt aber höchstens %d Gruppen. Tipp: Entferne einen dieser Konflikte oder erlaube kleinere bzw. mehr Gruppen.",
+				formatNameList(clique), maxGroups))
+			continue
+		}
+
+		// Mitschüler, die zu mindestens einem Mitglied der Konfliktgruppe passen.
+		inClique := make(map[string]bool)
+		for _, student := range clique {
+			inClique[student] = true
+		}
+		partnerSet := make(map[string]bool)
+		for _, student := range clique {
+			for _, partner := range compatible[student] {
+				if !inClique[partner] {
+					partnerSet[partner] = true
+				}
+			}
+		}
+		needed := len(clique) * (minSize - 1) // Jede der eigenen Gruppen braucht Mitglieder.
+		if len(partnerSet) < needed {
+			var partners []string
+			for partner := range partnerSet {
+				partners = append(partners, partner)
+			}
+			hints = append(hints, fmt.Sprintf(
+				"%s schließen sich alle gegenseitig aus und passen zusammen nur zu %d Mitschülern (%s). Für %d eigene Gruppen mit mindestens %d Personen wären %d nötig. Tipp: Entferne einen dieser Konflikte.",
+				formatNameList(clique), len(partners), formatNameList(partners), len(clique), minSize, needed))
+		}
+	}
+
+	// 3. Pflichtpartner, die zusammen zu viele für eine Gruppe sind.
+	for _, unit := range rules.splitIntoUnits(allStudents) {
+		if len(unit) > maxSize {
+			hints = append(hints, fmt.Sprintf(
+				"%s müssen zusammen in eine Gruppe, die Gruppen haben aber höchstens %d Personen. Tipp: Entferne einen Pflichtpartner im Abschnitt [%s] oder erlaube größere Gruppen.",
+				formatNameList(unit), maxSize, togetherSection))
+		}
+	}
+
+	// 4. Feste Ausgleichsregeln: Passen alle Schüler mit dem Merkmal in die Gruppen?
+	for _, rule := range rules.balance {
+		if rule.MaxPerGroup <= 0 {
+			continue
+		}
+		var matching []string
+		for _, student := range allStudents {
+			if rules.attribute(student, rule.Attribute) == rule.Value {
+				matching = append(matching, student)
+			}
+		}
+		if len(matching) > maxGroups*rule.MaxPerGroup {
+			hints = append(hints, fmt.Sprintf(
+				"Die Regel '%s' betrifft %d Schüler (%s), bei höchstens %d Gruppen haben aber nur %d Platz. Tipp: Erhöhe 'hoechstens' oder erlaube kleinere bzw. mehr Gruppen.",
+				rule.describe(), len(matching), formatNameList(matching), maxGroups, maxGroups*rule.MaxPerGroup))
+		}
+	}
+
+	return hints
+}
+
+// ############################################################################################
+// hasValidGroupWith prüft, ob es aus 'student' und seinen passenden Partnern eine gültige Gruppe
+// 		mit 'minSize' bis 'maxSize' Personen gibt (siehe isValidGroup): ohne Konflikte, innerhalb der
+// 		Ausgleichsregeln und mit allen Pflichtpartnern. Partner kommen deshalb nur als ganze Einheit dazu.
+func hasValidGroupWith(student string, partners []string, minSize, maxSize int, rules *groupRules) bool {
+	units := rules.splitIntoUnits(partners) // Die eigene Einheit fehlt, weil 'student' kein Partner ist.
+	steps := 0                               // Begrenzt die Suche, falls sehr viele Partner vorhanden sind.
+	var search func(group []string, from int) bool
+	search = func(group []string, from int) bool {
+		steps++
+		if !isValidGroup(group, rules) {
+			return false // Mit weiteren Mitgliedern wird die Gruppe nicht wieder gültig.
+		}
+		if len(group) >= minSize {
+			return true
+		}
+		if steps > exactSearchLimit {
+			return true // Im Zweifel keinen Hinweis ausgeben.
+		}
+		for i := from; i < len(units); i++ {
+			if len(group)+len(units[i]) <= maxSize && search(append(append([]string{}, group...), units[i]...), i+1) {
+				return true
+			}
+		}
+		return false
+	}
+	return search(rules.unitOf(student), 0)
+}
+
+// ############################################################################################
+// conflictCliques gibt alle maximalen Gruppen von mindestens zwei Schülern zurück,
+// 		die sich alle gegenseitig ausschließen (Bron-Kerbosch-Algorithmus auf dem Konfliktgraphen).
+func conflictCliques(allStudents []string, compatible map[string][]string) [][]string {
+	// Konfliktgraph: Wer verträgt sich mit wem nicht?
+	conflicts := make(map[string]map[string]bool)
+	for _, a := range allStudents {
+		conflicts[a] = make(map[string]bool)
+		isCompatible := make(map[string]bool)
+		for _, b := range compatible[a] {
+			isCompatible[b] = true
+		}
+		for _, b := range allStudents {
+			if a != b && !isCompatible[b] {
+				conflicts[a][b] = true
+			}
+		}
+	}
+
+	var cliques [][]string
+	var expand func(current, candidates, excluded []string)
+	expand = func(current, candidates, excluded []string) {
+		if len(cliques) >= maxReportedCliques {
+			return
+		}
+		if len(candidates) == 0 && len(excluded) == 0 {
+			if len(current) >= 2 { // Einzelne Schüler sind keine Konfliktgruppe.
+				cliques = append(cliques, append([]string{}, current...))
+			}
+			return
+		}
+		for len(candidates) > 0 {
+			student := candidates[0]
+			var nextCandidates, nextExcluded []string
+			for _, other := range candidates {
+				if conflicts[student][other] {
+					nextCandidates = append(nextCandidates, other)
+				}
+			}
+			for _, other := range excluded {
+				if conflicts[student][other] {
+					nextExcluded = append(nextExcluded, other)
+				}
+			}
+			expand(append(current, student), nextCandidates, nextExcluded)
+			candidates = candidates[1:]
+			excluded = append(excluded, student)
+		}
+	}
+	expand(nil, append([]string{}, allStudents...), nil)
+
+	// Größte Konfliktgruppen zuerst.
+	sort.SliceStable(cliques, func(i, j int) bool {
+		return len(cliques[i]) > len(cliques[j])
+	})
+	return cliques
+}
+
+// ############################################################################################
+// formatNameList gibt Namen sortiert und in Anführungszeichen aus (z.B. 'Anna', 'Ben').
+func formatNameList(names []string) string {
+	if len(names) == 0 {
+		return "niemandem"
+	}
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	quoted := make([]string, len(sorted))
+	for i, name := range sorted {
+		quoted[i] = "'" + name + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
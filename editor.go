@@ -0,0 +1,693 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"bufio"         // Zum Lesen ganzer Zeilen (Namen dürfen Leerzeichen enthalten).
+	"fmt"           // Für die Menüs und Meldungen.
+	"io"            // Zum Erkennen, dass keine Eingabe mehr kommt.
+	"os"            // Zum Lesen und Schreiben der Konfigurationsdatei.
+	"path/filepath" // Für den Dateinamen in den Meldungen.
+	"reflect"       // Zum Vergleichen der unverändert übernommenen Abschnitte.
+	"strconv"       // Zum Lesen der Auswahl im Menü.
+	"strings"       // Zum Vergleichen der Namen.
+
+	"github.com/pelletier/go-toml" // Zum Prüfen der neuen Fassung vor dem Speichern.
+)
+
+// ############################################################################################
+// editedSections sind die Abschnitte, die der Befehl 'bearbeiten' beim Speichern neu schreibt.
+// Alle anderen Abschnitte (z.B. [einstellungen], [import] und [[ausgleich]]) werden unverändert übernommen.
+var editedSections = []string{conflictsSection, togetherSection, avoidSection, preferSection}
+
+// editMenu enthält die Einträge des Hauptmenüs (Nummer = Index + 1, außer 'Beenden' mit 0).
+var editMenu = []string{
+	"Schülerliste und Konflikte anzeigen",
+	"Schüler hinzufügen",
+	"Schüler umbenennen",
+	"Schüler entfernen",
+	"Konflikt hinzufügen",
+	"Konflikt entfernen",
+	"Speichern",
+}
+
+// ############################################################################################
+// classEditor ist der Zustand des Befehls 'bearbeiten'.
+type classEditor struct {
+	config   *Config             // Die Konfiguration, die bearbeitet wird.
+	input    *bufio.Reader       // Eingaben aus dem Terminal.
+	imported bool                // Kommt die Schülerliste aus [import]? Dann kann sie hier nicht geändert werden.
+	changed  bool                // Gibt es Änderungen, die noch nicht gespeichert sind?
+	declared map[string][]string // Konflikte aus [konflikte], ohne die aus 'konfliktpaare' (config.Constraints enthält beide).
+	renamed  map[string]string   // Ursprünglicher Name → aktueller Name (damit Kommentare beim Eintrag bleiben).
+}
+
+// entryKey bezeichnet einen Eintrag der Datei (Abschnitt "" = ohne Abschnitt, z.B. 'schuelerliste').
+type entryKey struct {
+	Section string // Abschnitt des Eintrags.
+	Key     string // Schlüssel, meist ein Schülername.
+}
+
+// entryComment enthält die Kommentare, die beim Speichern mit ihrem Eintrag wandern.
+type entryComment struct {
+	Above    []string // Kommentarzeilen direkt über dem Eintrag.
+	Trailing string   // Kommentar am Ende der Zeile des Eintrags ("" = keiner).
+}
+
+// ############################################################################################
+// runEditCommand zeigt für den Befehl 'bearbeiten' ein Menü, mit dem die Schülerliste und die Konflikte
+// 		ohne TOML-Kenntnisse bearbeitet werden. Gespeichert wird erst mit 'Speichern',
+// 		dann wird die Datei im selben Format wie die Musterdatei neu geschrieben (siehe formatConfigFile).
+// Kommentare über einem Eintrag und am Ende seiner Zeile bleiben dabei erhalten (siehe carryComments),
+// 		für alle anderen wird vor dem Speichern nachgefragt.
+// Endet die Eingabe (z.B. Strg+D), wird das Programm ohne Speichern beendet.
+func runEditCommand(config *Config) error {
+	data, err := os.ReadFile(config.Path)
+	if err != nil {
+		return fmt.Errorf("❌ Fehler beim Lesen der Datei %s: %w", config.Path, err)
+	}
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return syntaxError(config.Path, err)
+	}
+	if version, _ := tree.GetPath([]string{schemaVersionKey}).(int64); version != currentSchemaVersion {
+		// Sonst würde die Datei beim Speichern nebenbei und ohne Sicherung umgestellt.
+		return fmt.Errorf("❌ %s ist noch im alten Dateiformat und konnte nicht umgestellt werden. Bitte zuerst die Konflikte in den Abschnitt [%s] verschieben", config.Path, conflictsSection)
+	}
+	editor := &classEditor{config: config, input: bufio.NewReader(os.Stdin), imported: tree.Has(importSection), renamed: make(map[string]string)}
+	editor.declared = make(map[string][]string)
+	if conflicts, ok := tree.Get(conflictsSection).(*toml.Tree); ok {
+		editor.declared = readStringListTable(conflicts, conflictsSection, nil) // Probleme meldet bereits das Laden.
+	}
+
+	fmt.Printf("=== Bearbeite %s\n", filepath.Base(config.Path))
+	checkStudentNames(config)
+	if len(config.Report.Issues) > 0 {
+		config.Report.print()
+	}
+	if editor.imported {
+		fmt.Printf("ℹ️ Die Schülerliste kommt aus dem Abschnitt [%s] und kann hier nicht geändert werden, nur die Konflikte.\n", importSection)
+	}
+	fmt.Printf("ℹ️ Beim Speichern werden die Schülerliste und die Abschnitte [%s], [%s], [%s] und [%s] neu geschrieben.\n",
+		conflictsSection, togetherSection, avoidSection, preferSection)
+	fmt.Println("   Kommentare über einem Eintrag bleiben erhalten, bevor andere verloren gehen, wird nachgefragt.")
+
+	for {
+		fmt.Println()
+		for i, entry := range editMenu {
+			fmt.Printf("  %d) %s\n", i+1, entry)
+		}
+		fmt.Println("  0) Beenden")
+		answer, err := editor.readLine("Auswahl: ")
+		if err == io.EOF { // Keine Eingabe mehr möglich.
+			if editor.changed {
+				fmt.Println("\n❗️ Eingabe beendet, die Änderungen wurden nicht gespeichert.")
+			}
+			return nil
+		}
+
+		switch answer {
+		case "":
+			continue // Nur Enter gedrückt: Menü nochmals zeigen.
+		case "0":
+			if editor.changed {
+				save, err := editor.confirm("Es gibt ungespeicherte Änderungen. Jetzt speichern?")
+				if err == nil && save {
+					if err := editor.save(); err != nil {
+						return err
+					}
+					if editor.changed {
+						continue // Speichern abgebrochen (z.B. wegen Kommentaren): zurück ins Menü.
+					}
+					return nil
+				}
+				fmt.Println("❗️ Die Änderungen wurden nicht gespeichert.")
+			}
+			return nil
+		case "1":
+			editor.show()
+		case "2":
+			err = editor.addStudents()
+		case "3":
+			err = editor.renameStudent()
+		case "4":
+			err = editor.removeStudent()
+		case "5":
+			err = editor.addConflict()
+		case "6":
+			err = editor.removeConflict()
+		case "7":
+			if err := editor.save(); err != nil {
+				fmt.Println(err) // Die Änderungen bleiben erhalten, z.B. für einen zweiten Versuch.
+			}
+		default:
+			fmt.Printf("❗️ '%s' gibt es nicht. Bitte eine Nummer zwischen 0 und %d eingeben.\n", answer, len(editMenu))
+		}
+		if err == io.EOF {
+			if editor.changed {
+				fmt.Println("\n❗️ Eingabe beendet, die Änderungen wurden nicht gespeichert.")
+			}
+			return nil
+		}
+	}
+}
+
+// ############################################################################################
+// readLine gibt eine Eingabezeile ohne Leerzeichen am Anfang und Ende zurück (io.EOF = keine Eingabe mehr).
+func (e *classEditor) readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := e.input.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return "", io.EOF
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// confirm stellt eine Ja/Nein-Frage. Nur 'j' oder 'ja' gilt als Ja.
+func (e *classEditor) confirm(question string) (bool, error) {
+	answer, err := e.readLine(question + " (j/n): ")
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "j" || answer == "ja", nil
+}
+
+// chooseStudent fragt nach einem Schüler (Nummer aus der Anzeige oder Name, ohne Groß-/Kleinschreibung).
+// Gibt "" zurück, wenn nur Enter gedrückt wurde (Abbrechen).
+func (e *classEditor) chooseStudent(prompt string) (string, error) {
+	students := e.config.Schuelerliste
+	for {
+		answer, err := e.readLine(prompt + " (Nummer oder Name, leer = abbrechen): ")
+		if err != nil || answer == "" {
+			return "", err
+		}
+		if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(students) {
+			return students[number-1], nil
+		}
+		if student := e.findStudent(answer); student != "" {
+			return student, nil
+		}
+		message := fmt.Sprintf("❗️ '%s' steht nicht in der Schülerliste.", answer)
+		if suggestions := suggestNames(answer, students); len(suggestions) > 0 {
+			message += fmt.Sprintf(" Meintest du %s?", strings.Join(quoteNames(suggestions), " oder "))
+		}
+		fmt.Println(message)
+	}
+}
+
+// findStudent sucht einen Namen in der Schülerliste ohne Beachtung von Groß-/Kleinschreibung ("" = nicht gefunden).
+func (e *classEditor) findStudent(name string) string {
+	for _, student := range e.config.Schuelerliste {
+		if strings.EqualFold(student, name) {
+			return student
+		}
+	}
+	return ""
+}
+
+// canChangeStudents meldet, dass die Schülerliste aus [import] kommt und hier nicht geändert werden kann.
+func (e *classEditor) canChangeStudents() bool {
+	if e.imported {
+		fmt.Printf("❗️ Die Schülerliste kommt aus dem Abschnitt [%s]. Bitte ändere die CSV-Datei der Schulverwaltung.\n", importSection)
+		return false
+	}
+	return true
+}
+
+// ############################################################################################
+// show gibt die Schülerliste nummeriert aus, jeweils mit den Konflikten des Schülers.
+func (e *classEditor) show() {
+	fmt.Printf("=== Schülerliste (%d Schüler):\n", len(e.config.Schuelerliste))
+	for i, student := range e.config.Schuelerliste {
+		line := fmt.Sprintf("  %2d) %s", i+1, student)
+		if conflicts := e.config.Constraints[student]; len(conflicts) > 0 {
+			line += fmt.Sprintf(" – nicht mit %s", formatNameList(conflicts))
+		}
+		fmt.Println(line)
+	}
+}
+
+// addStudents fragt so lange nach neuen Namen, bis nur Enter gedrückt wird.
+func (e *classEditor) addStudents() error {
+	if !e.canChangeStudents() {
+		return nil
+	}
+	for {
+		name, err := e.readLine("Name des neuen Schülers (leer = fertig): ")
+		if err != nil || name == "" {
+			return err
+		}
+		if existing := e.findStudent(name); existing != "" {
+			fmt.Printf("❗️ '%s' steht schon in der Schülerliste.\n", existing)
+			continue
+		}
+		e.config.Schuelerliste = append(e.config.Schuelerliste, name)
+		e.changed = true
+		fmt.Printf("✅ '%s' hinzugefügt.\n", name)
+	}
+}
+
+// renameStudent ändert einen Namen in der Schülerliste und in allen Einschränkungen.
+func (e *classEditor) renameStudent() error {
+	if !e.canChangeStudents() {
+		return nil
+	}
+	student, err := e.chooseStudent("Wen umbenennen?")
+	if err != nil || student == "" {
+		return err
+	}
+	name, err := e.readLine(fmt.Sprintf("Neuer Name für '%s' (leer = abbrechen): ", student))
+	if err != nil || name == "" || name == student {
+		return err
+	}
+	if existing := e.findStudent(name); existing != "" && existing != student { // Nur Groß-/Kleinschreibung ändern ist erlaubt.
+		fmt.Printf("❗️ '%s' steht schon in der Schülerliste.\n", existing)
+		return nil
+	}
+
+	config := e.config
+	for i := range config.Schuelerliste {
+		if config.Schuelerliste[i] == student {
+			config.Schuelerliste[i] = name
+		}
+	}
+	original := student
+	for old, current := range e.renamed {
+		if current == student {
+			original = old // Schon einmal umbenannt: die Kommentare gehören zum Namen in der Datei.
+		}
+	}
+	e.renamed[original] = name
+	for _, group := range config.ConflictPairs {
+		for i := range group {
+			if group[i] == student {
+				group[i] = name
+			}
+		}
+	}
+	if attributes, found := config.Attributes[student]; found {
+		delete(config.Attributes, student)
+		config.Attributes[name] = attributes
+	}
+	for _, lists := range []map[string][]string{config.Constraints, e.declared, config.Together} {
+		if list, found := lists[student]; found {
+			delete(lists, student)
+			lists[name] = list
+		}
+		for key, list := range lists {
+			for i := range list {
+				if list[i] == student {
+					lists[key][i] = name
+				}
+			}
+		}
+	}
+	for _, weighted := range []map[string]map[string]int{config.Avoid, config.Prefer} {
+		if weights, found := weighted[student]; found {
+			delete(weighted, student)
+			weighted[name] = weights
+		}
+		for _, weights := range weighted {
+			if weight, found := weights[student]; found {
+				delete(weights, student)
+				weights[name] = weight
+			}
+		}
+	}
+	e.changed = true
+	fmt.Printf("✅ '%s' heißt jetzt '%s'.\n", student, name)
+	return nil
+}
+
+// removeStudent entfernt einen Schüler nach Rückfrage aus der Schülerliste und aus allen Einschränkungen.
+func (e *classEditor) removeStudent() error {
+	if !e.canChangeStudents() {
+		return nil
+	}
+	student, err := e.chooseStudent("Wen entfernen?")
+	if err != nil || student == "" {
+		return err
+	}
+	remove, err := e.confirm(fmt.Sprintf("'%s' samt allen Konflikten und Wünschen entfernen?", student))
+	if err != nil || !remove {
+		return err
+	}
+
+	config := e.config
+	var remaining []string
+	for _, name := range config.Schuelerliste {
+		if name != student {
+			remaining = append(remaining, name)
+		}
+	}
+	config.Schuelerliste = remaining
+	delete(config.Attributes, student)
+	var groups [][]string
+	for _, group := range config.ConflictPairs {
+		if group = withoutName(group, student); len(group) >= 2 {
+			groups = append(groups, group)
+		}
+	}
+	config.ConflictPairs = groups
+	for _, lists := range []map[string][]string{config.Constraints, e.declared, config.Together} {
+		delete(lists, student)
+		for key := range lists {
+			removeFromList(lists, key, student)
+		}
+	}
+	for _, weighted := range []map[string]map[string]int{config.Avoid, config.Prefer} {
+		delete(weighted, student)
+		for key, weights := range weighted {
+			delete(weights, student)
+			if len(weights) == 0 {
+				delete(weighted, key)
+			}
+		}
+	}
+	e.changed = true
+	fmt.Printf("✅ '%s' entfernt.\n", student)
+	return nil
+}
+
+// ############################################################################################
+// addConflict trägt einen Konflikt zwischen zwei Schülern in beide Richtungen ein.
+func (e *classEditor) addConflict() error {
+	a, b, err := e.choosePair()
+	if err != nil || a == "" {
+		return err
+	}
+	if containsString(e.config.Constraints[a], b) && containsString(e.config.Constraints[b], a) {
+		fmt.Printf("ℹ️ '%s' und '%s' haben bereits einen Konflikt.\n", a, b)
+		return nil
+	}
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		for _, lists := range []map[string][]string{e.config.Constraints, e.declared} {
+			if !containsString(lists[pair[0]], pair[1]) {
+				lists[pair[0]] = append(lists[pair[0]], pair[1])
+			}
+		}
+	}
+	e.changed = true
+	fmt.Printf("✅ '%s' und '%s' kommen nicht mehr in dieselbe Gruppe.\n", a, b)
+	return nil
+}
+
+// removeConflict entfernt einen Konflikt zwischen zwei Schülern in beide Richtungen.
+func (e *classEditor) removeConflict() error {
+	a, b, err := e.choosePair()
+	if err != nil || a == "" {
+		return err
+	}
+	if !containsString(e.config.Constraints[a], b) && !containsString(e.config.Constraints[b], a) {
+		fmt.Printf("ℹ️ Zwischen '%s' und '%s' gibt es keinen Konflikt.\n", a, b)
+		return nil
+	}
+	for _, lists := range []map[string][]string{e.config.Constraints, e.declared} {
+		removeFromList(lists, a, b)
+		removeFromList(lists, b, a)
+	}
+	// Eine Gruppe aus 'konfliktpaare' mit beiden wird aufgeteilt, damit die übrigen Konflikte bleiben:
+	// 		aus ["A", "B", "C"] werden ["B", "C"] und ["A", "C"].
+	var groups [][]string
+	for _, group := range e.config.ConflictPairs {
+		if !containsString(group, a) || !containsString(group, b) {
+			groups = append(groups, group)
+			continue
+		}
+		if len(group) > 2 {
+			groups = append(groups, withoutName(group, a), withoutName(group, b))
+			fmt.Printf("ℹ️ Die Gruppe %s in '%s' wird dafür aufgeteilt.\n", formatStringSliceToTomlArray(group), conflictPairsKey)
+		}
+	}
+	e.config.ConflictPairs = groups
+	e.changed = true
+	fmt.Printf("✅ Der Konflikt zwischen '%s' und '%s' ist entfernt.\n", a, b)
+	return nil
+}
+
+// choosePair fragt nach zwei verschiedenen Schülern (beide "" = abgebrochen).
+func (e *classEditor) choosePair() (string, string, error) {
+	a, err := e.chooseStudent("Erster Schüler")
+	if err != nil || a == "" {
+		return "", "", err
+	}
+	if conflicts := e.config.Constraints[a]; len(conflicts) > 0 {
+		fmt.Printf("   '%s' hat Konflikte mit %s.\n", a, formatNameList(conflicts))
+	}
+	for {
+		b, err := e.chooseStudent("Zweiter Schüler")
+		if err != nil || b == "" {
+			return "", "", err
+		}
+		if b != a {
+			return a, b, nil
+		}
+		fmt.Println("❗️ Bitte einen anderen Schüler wählen.")
+	}
+}
+
+// withoutName gibt eine Kopie der Liste ohne 'name' zurück.
+func withoutName(list []string, name string) []string {
+	var remaining []string
+	for _, item := range list {
+		if item != name {
+			remaining = append(remaining, item)
+		}
+	}
+	return remaining
+}
+
+// removeFromList entfernt 'name' aus der Liste von 'key'. Leere Listen werden ganz entfernt.
+func removeFromList(lists map[string][]string, key string, name string) {
+	remaining := withoutName(lists[key], name)
+	if len(remaining) == 0 {
+		delete(lists, key)
+		return
+	}
+	lists[key] = remaining
+}
+
+// ############################################################################################
+// save schreibt die bearbeitete Konfiguration zurück. Abschnitte, die hier nicht bearbeitet werden,
+// 		werden unverändert aus der aktuellen Datei übernommen (siehe sectionTexts).
+// 'konfliktpaare' bleiben Gruppen, Kommentare wandern mit ihrem Eintrag (siehe carryComments).
+// Gingen dabei andere Kommentare verloren, wird vorher nachgefragt. Bei 'nein' bleibt 'changed' gesetzt.
+// Die neue Fassung wird vor dem Schreiben geprüft, die alte als '.bak' gesichert.
+func (e *classEditor) save() error {
+	path := e.config.Path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("❌ Fehler beim Lesen der Datei %s: %w", path, err)
+	}
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return syntaxError(path, err)
+	}
+	preserved := sectionTexts(string(data), tree, editedSections)
+
+	output := *e.config
+	output.Constraints = e.declared // Die Konflikte aus 'konfliktpaare' stehen in output.ConflictPairs.
+	if e.imported {
+		output.Schuelerliste = nil // Die Schülerliste kommt weiterhin aus [import].
+	}
+	content := formatConfigFile(&output, preserved)
+	content = carryComments(content, readEntryComments(string(data), tree), e.currentName)
+
+	// Das Ergebnis muss gültig sein und die übernommenen Abschnitte unverändert enthalten.
+	newTree, err := toml.Load(content)
+	if err != nil {
+		return fmt.Errorf("❌ Die neue Fassung wäre ungültig, '%s' bleibt unverändert: %w", path, err)
+	}
+	for section := range preserved {
+		if !reflect.DeepEqual(sectionValue(tree, section), sectionValue(newTree, section)) {
+			return fmt.Errorf("❌ Der Abschnitt [%s] konnte nicht unverändert übernommen werden, '%s' bleibt unverändert", section, path)
+		}
+	}
+
+	if lost := lostComments(string(data), content); len(lost) > 0 {
+		fmt.Println("❗️ Diese Kommentare gehören zu keinem Eintrag mehr und gehen beim Speichern verloren:")
+		for _, comment := range lost {
+			fmt.Printf("   %s\n", comment)
+		}
+		save, err := e.confirm("Trotzdem speichern?")
+		if err != nil || !save {
+			fmt.Printf("❗️ Nicht gespeichert, '%s' bleibt unverändert. Die Änderungen sind noch nicht verloren.\n", path)
+			return nil
+		}
+	}
+
+	backupPath, err := writeBackup(path, "", data)
+	if err != nil {
+		return fmt.Errorf("❌ Fehler beim Schreiben der Sicherung '%s': %w", backupPath, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("❌ Fehler beim Schreiben der Datei '%s': %w", path, err)
+	}
+	e.changed = false
+	fmt.Printf("✅ %s gespeichert (Sicherung: %s).\n", path, backupPath)
+	return nil
+}
+
+// currentName gibt den aktuellen Namen zu einem Namen aus der Datei zurück (nach 'Schüler umbenennen').
+func (e *classEditor) currentName(name string) string {
+	if current, found := e.renamed[name]; found {
+		return current
+	}
+	return name
+}
+
+// ############################################################################################
+// readEntryComments sammelt die Kommentare zu den Schlüsseln ohne Abschnitt (topLevelKeys) und zu allen Einträgen
+// 		der Abschnitte aus editedSections: die Kommentarzeilen direkt darüber und den Kommentar am Zeilenende.
+func readEntryComments(content string, tree *toml.Tree) map[entryKey]entryComment {
+	lines := strings.Split(content, "\n")
+	comments := make(map[entryKey]entryComment)
+	collect := func(table *toml.Tree, section string, key string) {
+		line := entryLine(content, table, section, key)
+		if line < 1 || line > len(lines) {
+			return
+		}
+		var comment entryComment
+		for i := line - 2; i >= 0 && isCommentLine(lines[i]); i-- {
+			comment.Above = append([]string{strings.TrimSpace(lines[i])}, comment.Above...)
+		}
+		comment.Trailing = lineComment(lines[line-1])
+		if len(comment.Above) > 0 || comment.Trailing != "" {
+			comments[entryKey{Section: section, Key: key}] = comment
+		}
+	}
+	for _, key := range topLevelKeys {
+		if tree.Has(key) {
+			collect(tree, "", key)
+		}
+	}
+	for _, section := range editedSections {
+		table, _ := tree.Get(section).(*toml.Tree)
+		for _, key := range keysOf(table) {
+			collect(table, section, key)
+		}
+	}
+	return comments
+}
+
+// carryComments fügt die Kommentare aus readEntryComments wieder bei ihrem Eintrag in die neue Fassung ein.
+// Kommentare, die dort schon stehen (z.B. die Erklärungen der Musterdatei), werden nicht verdoppelt.
+// 'currentName' liefert den Namen eines Schülers nach dem Umbenennen. Fehlt ein Eintrag, fehlen auch seine Kommentare.
+func carryComments(content string, comments map[entryKey]entryComment, currentName func(string) string) string {
+	tree, err := toml.Load(content)
+	if err != nil {
+		return content // Die neue Fassung wird beim Speichern ohnehin geprüft.
+	}
+	lines := strings.Split(content, "\n")
+	known := make(map[string]bool) // Kommentare, die schon in der neuen Fassung stehen.
+	for _, line := range lines {
+		if comment := lineComment(line); comment != "" {
+			known[comment] = true
+		}
+	}
+
+	above := make(map[int][]string) // Zeile (ab 0) → Kommentarzeilen, die davor eingefügt werden.
+	for entry, comment := range comments {
+		table, key := tree, entry.Key
+		if entry.Section != "" {
+			table, _ = tree.Get(entry.Section).(*toml.Tree)
+			key = currentName(entry.Key)
+		}
+		if table == nil || table.GetPath([]string{key}) == nil {
+			continue // Eintrag entfernt: lostComments meldet die Kommentare.
+		}
+		line := entryLine(content, table, entry.Section, key) - 1
+		if line < 0 || line >= len(lines) {
+			continue // Eintrag nicht gefunden: lostComments meldet die Kommentare.
+		}
+		for _, text := range comment.Above {
+			if !known[text] {
+				above[line] = append(above[line], text)
+			}
+		}
+		if comment.Trailing != "" && !known[comment.Trailing] {
+			if strings.HasSuffix(strings.TrimSpace(lines[line]), "[") { // Mehrzeiliges Array: Kommentar darüber.
+				above[line] = append(above[line], comment.Trailing)
+			} else {
+				lines[line] += " " + comment.Trailing
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for i, line := range lines {
+		for _, text := range above[i] {
+			sb.WriteString(text + "\n")
+		}
+		sb.WriteString(line)
+		if i < len(lines)-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// entryLine gibt die Zeile (ab 1) zurück, in der 'key' steht (0 = nicht gefunden).
+// Für manche Werte (z.B. eine Schülerliste mit Inline-Tabellen) kennt go-toml die Position nicht,
+// 		dann wird die Zeile im Text gesucht.
+func entryLine(content string, table *toml.Tree, section string, key string) int {
+	position := table.GetPositionPath([]string{key})
+	if position.Invalid() {
+		position = findKeyPosition(content, section, key)
+	}
+	if position.Invalid() {
+		return 0
+	}
+	return position.Line
+}
+
+// lostComments gibt die Kommentare der alten Fassung zurück, die in der neuen nicht mehr vorkommen.
+func lostComments(oldContent string, newContent string) []string {
+	known := make(map[string]bool)
+	for _, line := range strings.Split(newContent, "\n") {
+		if comment := lineComment(line); comment != "" {
+			known[comment] = true
+		}
+	}
+	var lost []string
+	for _, line := range strings.Split(oldContent, "\n") {
+		if comment := lineComment(line); comment != "" && !known[comment] {
+			known[comment] = true // Jeden Kommentar nur einmal melden.
+			lost = append(lost, comment)
+		}
+	}
+	return lost
+}
+
+// lineComment gibt den Kommentar einer Zeile ab '#' zurück ("" = keiner). '#' in Zeichenketten zählt nicht.
+func lineComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"', '\'':
+			end := stringEnd(line, i)
+			if end < 0 {
+				return ""
+			}
+			i = end
+		case '#':
+			return strings.TrimSpace(line[i:])
+		}
+	}
+	return ""
+}
+
+// sectionValue gibt den Inhalt eines Abschnitts zum Vergleichen zurück (Tabellen als Map).
+func sectionValue(tree *toml.Tree, section string) interface{} {
+	switch value := tree.GetPath([]string{section}).(type) {
+	case *toml.Tree:
+		return value.ToMap()
+	case []*toml.Tree:
+		tables := make([]map[string]interface{}, len(value))
+		for i, table := range value {
+			tables[i] = table.ToMap()
+		}
+		return tables
+	default:
+		return value
+	}
+}
@@ -0,0 +1,91 @@
+package main // Tests für den Befehl 'bearbeiten' in editor.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"reflect" // Zum Vergleichen der verlorenen Kommentare.
+	"testing" // Test-Framework von Go.
+
+	"github.com/pelletier/go-toml" // Zum Parsen der alten Fassung wie beim Speichern.
+)
+
+// ############################################################################################
+// TestLineComment prüft, dass nur '#' außerhalb von Zeichenketten einen Kommentar beginnt.
+func TestLineComment(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`"Anna" = ["Ben"] # seit Mai`, "# seit Mai"},
+		{`"Anna#1" = ["Ben"]`, ""},
+		{`titel = 'Gruppe #1' # Aushang`, "# Aushang"},
+		{"  # ganze Zeile  ", "# ganze Zeile"},
+		{`"ohne Ende # kein Kommentar`, ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := lineComment(test.line); got != test.want {
+			t.Errorf("lineComment(%q) = %q, erwartet %q", test.line, got, test.want)
+		}
+	}
+}
+
+// TestCarryComments prüft, dass Kommentare beim Neuschreiben mit ihrem Eintrag wandern.
+// Die übrigen müssen als verloren gemeldet werden.
+func TestCarryComments(t *testing.T) {
+	renamed := map[string]string{"Ben": "Benno"}
+	currentName := func(name string) string {
+		if current, found := renamed[name]; found {
+			return current
+		}
+		return name
+	}
+
+	tests := []struct {
+		name     string
+		old      string
+		new      string // Neu geschriebene Fassung ohne Kommentare.
+		want     string
+		wantLost []string
+	}{
+		{
+			name: "über dem Eintrag und am Zeilenende, auch nach dem Umbenennen",
+			old:  "# Klasse 5b\nversion = 2\n[konflikte]\n# Ben mag Dario nicht\n\"Ben\" = [\"Dario\"] # seit Mai\n\"Dario\" = [\"Ben\"]\n",
+			new:  "version = 2\n[konflikte]\n\"Benno\" = [\"Dario\"]\n\"Dario\" = [\"Benno\"]\n",
+			want: "# Klasse 5b\nversion = 2\n[konflikte]\n# Ben mag Dario nicht\n\"Benno\" = [\"Dario\"] # seit Mai\n\"Dario\" = [\"Benno\"]\n",
+		},
+		{
+			name: "vorhandene Kommentare nicht verdoppeln",
+			old:  "version = 2\n# Erklärung\nschuelerliste = [\"Anna\"]\n",
+			new:  "version = 2\n# Erklärung\nschuelerliste = [\"Anna\", \"Carla\"]\n",
+			want: "version = 2\n# Erklärung\nschuelerliste = [\"Anna\", \"Carla\"]\n",
+		},
+		{
+			name: "mehrzeiliges Array: Kommentar am Zeilenende kommt darüber",
+			old:  "schuelerliste = [\"Anna\"] # Stand August\n",
+			new:  "schuelerliste = [\n    { name = \"Anna\", niveau = \"stark\" },\n]\n",
+			want: "# Stand August\nschuelerliste = [\n    { name = \"Anna\", niveau = \"stark\" },\n]\n",
+		},
+		{
+			name:     "entfernter Eintrag und freie Kommentare gehen verloren",
+			old:      "[konflikte]\n# Streit\n\"Emil\" = [\"Anna\"]\n\"Anna\" = [\"Emil\"] # Pause\n\n# Ende der Konflikte\n",
+			new:      "[konflikte]\n",
+			want:     "[konflikte]\n",
+			wantLost: []string{"# Streit", "# Pause", "# Ende der Konflikte"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree, err := toml.Load(test.old)
+			if err != nil {
+				t.Fatalf("Testdatei ungültig: %v", err)
+			}
+			got := carryComments(test.new, readEntryComments(test.old, tree), currentName)
+			if got != test.want {
+				t.Errorf("Ergebnis:\n%s\nerwartet:\n%s", got, test.want)
+			}
+			if lost := lostComments(test.old, got); !reflect.DeepEqual(lost, test.wantLost) {
+				t.Errorf("verlorene Kommentare = %q, erwartet %q", lost, test.wantLost)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"math/rand" // Zum Mischen der Schülerliste, damit auch die exakte Suche verschiedene Lösungen liefert.
+	"sort"      // Zum Sortieren der Schüler nach Anzahl Konflikten und der Größen-Kombinationen.
+)
+
+// ############################################################################################
+// exactSearchLimit begrenzt die Anzahl der Suchschritte der exakten Suche.
+// Bei sehr großen Klassen mit vielen Konflikten kann die vollständige Suche sonst sehr lange dauern.
+// Die Grenze gilt für alle Größen-Kombinationen zusammen (siehe exactBudget).
+const exactSearchLimit = 2000000
+
+// exactBudget zählt die Suchschritte, die sich mehrere exakte Suchen teilen:
+// 		alle Größen-Kombinationen eines Szenarios, bei einem Rotationsplan zusätzlich alle Runden.
+type exactBudget struct {
+	steps int // Bisher verbrauchte Suchschritte (höchstens 'exactSearchLimit').
+}
+
+// ############################################################################################
+// exactStatus beschreibt das Ergebnis der exakten Suche.
+type exactStatus int
+
+const (
+	exactFound      exactStatus = iota // Eine vollständige, gültige Einteilung wurde gefunden.
+	exactInfeasible                    // Es wurde bewiesen, dass keine vollständige Einteilung existiert.
+	exactAborted                       // Die Suche wurde wegen 'exactSearchLimit' abgebrochen, das Ergebnis ist offen.
+)
+
+// ############################################################################################
+// solveExact sucht mit Backtracking eine vollständige Einteilung aller Schüler nach dem Szenario.
+// Im Gruppengröße-Modus sind alle Gruppengrößen zwischen 'MinSize' und 'MaxSize' erlaubt,
+// 		wobei Kombinationen mit möglichst vielen Gruppen der Zielgröße zuerst probiert werden.
+// Im "Anzahl Gruppen"-Modus sind die Größen durch groupSizesForCount fest vorgegeben.
+// Anders als formGroups findet diese Suche immer eine Lösung, wenn es eine gibt.
+// Pflichtpartner werden als Einheit behandelt und nie getrennt.
+// Die Suchschritte werden von 'budget' abgezogen; ist es aufgebraucht, wird die Suche abgebrochen.
+func solveExact(allStudents []string, scenario groupScenario, rules *groupRules, rng *rand.Rand, budget *exactBudget) ([][]string, exactStatus) {
+	if len(allStudents) == 0 {
+		return nil, exactFound // Nichts zu tun.
+	}
+
+	var sizeOptions [][]int // Alle Kombinationen von Gruppengrößen, die probiert werden.
+	if scenario.GroupCount > 0 {
+		groupCount := scenario.GroupCount
+		if groupCount > len(allStudents) {
+			groupCount = len(allStudents)
+		}
+		sizeOptions = [][]int{groupSizesForCount(len(allStudents), groupCount)}
+	} else {
+		sizeOptions = sizeCombinations(len(allStudents), scenario.TargetSize, scenario.MinSize, scenario.MaxSize)
+	}
+
+	searcher := newExactSearcher(allStudents, rules, rng)
+	searcher.budget = budget
+	aborted := false
+	for _, sizes := range sizeOptions {
+		groups, status := searcher.solve(sizes)
+		switch status {
+		case exactFound:
+			return groups, exactFound
+		case exactAborted:
+			aborted = true // Diese Kombination ist offen, die anderen werden trotzdem probiert.
+		}
+	}
+	if aborted {
+		return nil, exactAborted
+	}
+	return nil, exactInfeasible
+}
+
+// ############################################################################################
+// sizeCombinations gibt alle Kombinationen von Gruppengrößen zwischen 'minSize' und 'maxSize' zurück,
+// 		die zusammen genau 'studentCount' Schüler ergeben.
+// Die Kombinationen sind absteigend sortiert und nach der Abweichung von der Zielgröße geordnet:
+// 		Zuerst kommen Kombinationen, die der Zielgröße am nächsten sind.
+func sizeCombinations(studentCount, targetSize, minSize, maxSize int) [][]int {
+	if minSize < 1 || minSize > targetSize { // Gleiche Korrektur wie in formGroups.
+		minSize = targetSize
+	}
+	if maxSize < targetSize {
+		maxSize = targetSize
+	}
+
+	var combinations [][]int
+	var current []int
+	var build func(remaining int, largest int)
+	build = func(remaining int, largest int) { // Erzeugt Kombinationen mit absteigenden Größen.
+		if remaining == 0 {
+			combinations = append(combinations, append([]int{}, current...))
+			return
+		}
+		for size := largest; size >= minSize; size-- {
+			if size > remaining {
+				continue
+			}
+			current = append(current, size)
+			build(remaining-size, size)
+			current = current[:len(current)-1]
+		}
+	}
+	build(studentCount, maxSize)
+
+	sort.SliceStable(combinations, func(i, j int) bool {
+		return sizeDeviation(combinations[i], targetSize) < sizeDeviation(combinations[j], targetSize)
+	})
+	return combinations
+}
+
+// ############################################################################################
+// exactSearcher enthält den Zustand der Backtracking-Suche.
+// Eingeteilt werden Einheiten (ein Schüler samt seinen Pflichtpartnern), die intern
+// 		über ihren Index angesprochen werden. Konflikte zwischen Einheiten stehen in einer Matrix.
+type exactSearcher struct {
+	units    [][]string   // Einheiten, die am stärksten eingeschränkten zuerst.
+	conflict [][]bool     // conflict[a][b] ist true, wenn die Einheiten a und b nicht in dieselbe Gruppe dürfen.
+	assigned []bool       // Welche Einheiten bereits einer Gruppe zugeteilt sind.
+	groups   [][]int      // Die bisher gebildeten Gruppen (als Indizes von Einheiten).
+	budget   *exactBudget // Gemeinsame Anzahl der Suchschritte (für 'exactSearchLimit').
+	broken   bool         // true, wenn eine Einheit in sich einen Konflikt hat (dann gibt es keine Lösung).
+	rules    *groupRules  // Für die Prüfung der festen Ausgleichsregeln.
+}
+
+// newExactSearcher bereitet die Suche vor: Schüler mischen, in Einheiten aufteilen,
+// 		nach Konflikten sortieren und die Konfliktmatrix aufbauen.
+func newExactSearcher(allStudents []string, rules *groupRules, rng *rand.Rand) *exactSearcher {
+	students := make([]string, len(allStudents))
+	copy(students, allStudents)
+	rng.Shuffle(len(students), func(i, j int) { // Zufällige Reihenfolge bei gleich vielen Konflikten.
+		students[i], students[j] = students[j], students[i]
+	})
+	units := rules.splitIntoUnits(students)
+
+	unitsConflict := func(a, b []string) bool { // Steht ein Mitglied von a mit einem Mitglied von b in Konflikt?
+		for _, studentA := range a {
+			for _, studentB := range b {
+				if rules.conflict(studentA, studentB) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// Einheiten mit vielen Konflikten werden zuerst eingeteilt, das verkleinert den Suchbaum stark.
+	degree := make([]int, len(units))
+	for a := range units {
+		for b := range units {
+			if a != b && unitsConflict(units[a], units[b]) {
+				degree[a]++
+			}
+		}
+	}
+	order := make([]int, len(units))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return degree[order[i]] > degree[order[j]]
+	})
+	sortedUnits := make([][]string, len(units))
+	for i, idx := range order {
+		sortedUnits[i] = units[idx]
+	}
+
+	searcher := &exactSearcher{units: sortedUnits, rules: rules}
+	searcher.conflict = make([][]bool, len(sortedUnits))
+	for a := range sortedUnits {
+		searcher.conflict[a] = make([]bool, len(sortedUnits))
+		for b := range sortedUnits {
+			searcher.conflict[a][b] = a != b && unitsConflict(sortedUnits[a], sortedUnits[b])
+		}
+		if unitsConflict(sortedUnits[a], sortedUnits[a]) || rules.exceedsBalanceLimits(sortedUnits[a]) { // Einheit verletzt selbst eine Regel.
+			searcher.broken = true
+		}
+	}
+	return searcher
+}
+
+// solve sucht eine Einteilung mit genau den Gruppengrößen aus 'sizes'.
+func (s *exactSearcher) solve(sizes []int) ([][]string, exactStatus) {
+	if s.broken {
+		return nil, exactInfeasible
+	}
+	s.assigned = make([]bool, len(s.units))
+	s.groups = nil
+
+	remaining := make(map[int]int) // Wie viele Gruppen jeder Größe noch gebildet werden müssen.
+	for _, size := range sizes {
+		remaining[size]++
+	}
+
+	found, aborted := s.fillNextGroup(remaining)
+	if aborted {
+		return nil, exactAborted
+	}
+	if !found {
+		return nil, exactInfeasible
+	}
+
+	// Indizes zurück in Namen übersetzen.
+	result := make([][]string, len(s.groups))
+	for i, group := range s.groups {
+		for _, idx := range group {
+			result[i] = append(result[i], s.units[idx]...)
+		}
+	}
+	return result, exactFound
+}
+
+// fillNextGroup eröffnet eine neue Gruppe mit der ersten noch freien Einheit.
+// Da diese Einheit sowieso in irgendeine Gruppe muss, werden gleichwertige Lösungen
+// 		nicht mehrfach durchsucht (Symmetriebrechung). Verzweigt wird nur über die Gruppengröße.
+func (s *exactSearcher) fillNextGroup(remaining map[int]int) (bool, bool) {
+	first := -1
+	for idx, used := range s.assigned {
+		if !used {
+			first = idx
+			break
+		}
+	}
+	if first == -1 { // Alle Schüler sind eingeteilt: Lösung gefunden.
+		return true, false
+	}
+
+	// Größen in absteigender Reihenfolge probieren, damit das Ergebnis stabil ist.
+	var sizes []int
+	for size, count := range remaining {
+		if count > 0 && size >= len(s.units[first]) {
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+
+	for _, size := range sizes {
+		remaining[size]--
+		s.assigned[first] = true
+		found, aborted := s.extendGroup([]int{first}, len(s.units[first]), first+1, size, remaining)
+		s.assigned[first] = false
+		remaining[size]++
+		if found || aborted {
+			return found, aborted
+		}
+	}
+	return false, false
+}
+
+// extendGroup ergänzt die angefangene Gruppe 'group' (mit 'members' Personen) bis zur Größe 'size'.
+// Kandidaten werden nur ab Index 'from' gewählt, damit jede Gruppe nur einmal gebildet wird.
+// Sobald ein Kandidat mit einem Gruppenmitglied in Konflikt steht, wird dieser Zweig abgeschnitten.
+func (s *exactSearcher) extendGroup(group []int, members int, from int, size int, remaining map[int]int) (bool, bool) {
+	if s.budget.steps >= exactSearchLimit {
+		return false, true // Suche abbrechen.
+	}
+	s.budget.steps++
+
+	if members == size { // Gruppe ist voll: nächste Gruppe eröffnen.
+		s.groups = append(s.groups, append([]int{}, group...))
+		found, aborted := s.fillNextGroup(remaining)
+		if !found {
+			s.groups = s.groups[:len(s.groups)-1]
+		}
+		return found, aborted
+	}
+
+	for candidate := from; candidate < len(s.units); candidate++ {
+		if s.assigned[candidate] || members+len(s.units[candidate]) > size || s.conflictsWithGroup(candidate, group) {
+			continue
+		}
+		if s.exceedsLimitsWith(candidate, group) { // Feste Ausgleichsregel würde verletzt.
+			continue
+		}
+		s.assigned[candidate] = true
+		found, aborted := s.extendGroup(append(group, candidate), members+len(s.units[candidate]), candidate+1, size, remaining)
+		s.assigned[candidate] = false
+		if found || aborted {
+			return found, aborted
+		}
+	}
+	return false, false
+}
+
+// exceedsLimitsWith prüft, ob die Gruppe mit dem Kandidaten eine feste Ausgleichsregel verletzt.
+func (s *exactSearcher) exceedsLimitsWith(candidate int, group []int) bool {
+	if len(s.rules.balance) == 0 {
+		return false // Schneller Weg ohne Ausgleichsregeln.
+	}
+	students := append([]string{}, s.units[candidate]...)
+	for _, member := range group {
+		students = append(students, s.units[member]...)
+	}
+	return s.rules.exceedsBalanceLimits(students)
+}
+
+// conflictsWithGroup prüft, ob eine Einheit mit einer Einheit der Gruppe in Konflikt steht.
+func (s *exactSearcher) conflictsWithGroup(candidate int, group []int) bool {
+	for _, member := range group {
+		if s.conflict[candidate][member] {
+			return true
+		}
+	}
+	return false
+}
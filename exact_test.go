@@ -0,0 +1,150 @@
+package main // Tests für die exakte Suche in exact.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"math/rand" // Für eine feste Zufallsquelle pro Testfall.
+	"reflect"   // Zum Vergleichen der Kombinationen.
+	"testing"   // Test-Framework von Go.
+)
+
+// ############################################################################################
+// TestSizeCombinations prüft die Kombinationen von Gruppengrößen und ihre Reihenfolge.
+func TestSizeCombinations(t *testing.T) {
+	tests := []struct {
+		name                                       string
+		studentCount, targetSize, minSize, maxSize int
+		want                                       [][]int
+	}{
+		{"alle Kombinationen, nächste zuerst", 10, 3, 2, 4, [][]int{{4, 3, 3}, {3, 3, 2, 2}, {4, 4, 2}, {4, 2, 2, 2}, {2, 2, 2, 2, 2}}},
+		{"genau aufgehend", 6, 2, 2, 2, [][]int{{2, 2, 2}}},
+		{"unmöglich", 5, 3, 3, 3, nil},
+		{"unsinnige Grenzen wie in formGroups", 6, 3, 0, 1, [][]int{{3, 3}}},
+		{"keine Schüler", 0, 3, 2, 4, [][]int{{}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := sizeCombinations(test.studentCount, test.targetSize, test.minSize, test.maxSize)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("sizeCombinations(%d, %d, %d, %d) = %v, erwartet %v",
+					test.studentCount, test.targetSize, test.minSize, test.maxSize, got, test.want)
+			}
+			// Die erste Kombination hat die kleinste mögliche Abweichung (siehe minimalSizeDeviation).
+			if len(got) > 0 {
+				if first, minimal := sizeDeviation(got[0], test.targetSize), minimalSizeDeviation(test.studentCount, test.targetSize, test.minSize, test.maxSize); first != minimal {
+					t.Errorf("erste Kombination mit Abweichung %d, minimalSizeDeviation ergibt %d", first, minimal)
+				}
+			}
+		})
+	}
+}
+
+// ############################################################################################
+// TestSolveExact prüft, dass die exakte Suche Lösungen findet und Unmöglichkeit beweist.
+func TestSolveExact(t *testing.T) {
+	// Jeder Schüler hat mit allen anderen einen Konflikt.
+	allConflicts := func(students []string) map[string][]string {
+		constraints := make(map[string][]string)
+		for _, a := range students {
+			for _, b := range students {
+				if a != b {
+					constraints[a] = append(constraints[a], b)
+				}
+			}
+		}
+		return constraints
+	}
+
+	tests := []struct {
+		name        string
+		students    []string
+		constraints map[string][]string
+		together    map[string][]string
+		scenario    groupScenario
+		want        exactStatus
+		wantSizes   []int // Nur bei exactFound.
+	}{
+		{
+			name:     "ohne Einschränkungen",
+			students: testStudents(10),
+			scenario: groupScenario{TargetSize: 3, MinSize: 2, MaxSize: 4},
+			want:     exactFound, wantSizes: []int{4, 3, 3},
+		},
+		{
+			name:        "mit Konflikten",
+			students:    testStudents(6),
+			constraints: map[string][]string{"S1": {"S2", "S3", "S4"}, "S2": {"S1"}, "S3": {"S1"}, "S4": {"S1"}},
+			scenario:    groupScenario{TargetSize: 3, MinSize: 2, MaxSize: 4},
+			want:        exactFound, wantSizes: []int{3, 3},
+		},
+		{
+			name:        "alle im Konflikt",
+			students:    testStudents(3),
+			constraints: allConflicts(testStudents(3)),
+			scenario:    groupScenario{TargetSize: 2, MinSize: 2, MaxSize: 3},
+			want:        exactInfeasible,
+		},
+		{
+			name:     "Pflichtpartner größer als erlaubt",
+			students: testStudents(6),
+			together: map[string][]string{"S1": {"S2", "S3"}, "S2": {"S1"}, "S3": {"S1"}},
+			scenario: groupScenario{TargetSize: 2, MinSize: 2, MaxSize: 2},
+			want:     exactInfeasible,
+		},
+		{
+			name:     "Anzahl Gruppen",
+			students: testStudents(11),
+			scenario: groupScenario{GroupCount: 3},
+			want:     exactFound, wantSizes: []int{4, 4, 3},
+		},
+		{
+			name:     "keine Schüler",
+			students: nil,
+			scenario: groupScenario{TargetSize: 3, MinSize: 2, MaxSize: 4},
+			want:     exactFound, wantSizes: []int{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules := testRules(test.students, test.constraints, test.together)
+			groups, status := solveExact(test.students, test.scenario, rules, rand.New(rand.NewSource(1)), &exactBudget{})
+			if status != test.want {
+				t.Fatalf("Status = %d, erwartet %d", status, test.want)
+			}
+			if status != exactFound {
+				return
+			}
+			if got := groupSizes(groups); !reflect.DeepEqual(got, test.wantSizes) {
+				t.Errorf("Gruppengrößen = %v, erwartet %v", got, test.wantSizes)
+			}
+			for _, group := range groups {
+				if !isValidGroup(group, rules) {
+					t.Errorf("ungültige Gruppe %v", group)
+				}
+			}
+		})
+	}
+}
+
+// TestSolveExactBudget prüft, dass sich mehrere exakte Suchen die Suchschritte teilen.
+// Ist das Budget aufgebraucht, wird jede weitere Suche sofort abgebrochen.
+func TestSolveExactBudget(t *testing.T) {
+	students := testStudents(6)
+	scenario := groupScenario{TargetSize: 3, MinSize: 3, MaxSize: 3}
+	rules := testRules(students, nil, nil)
+	budget := &exactBudget{}
+	var steps []int // Stand des Budgets nach jeder Suche.
+	for round := 1; round <= 2; round++ {
+		if _, status := solveExact(students, scenario, rules, rand.New(rand.NewSource(1)), budget); status != exactFound {
+			t.Fatalf("Suche %d: Status = %d, erwartet %d", round, status, exactFound)
+		}
+		steps = append(steps, budget.steps)
+	}
+	if steps[0] == 0 || steps[1] != 2*steps[0] {
+		t.Errorf("Suchschritte nach zwei gleichen Suchen = %v, erwartet [n 2n]", steps)
+	}
+
+	budget.steps = exactSearchLimit
+	if _, status := solveExact(students, scenario, rules, rand.New(rand.NewSource(1)), budget); status != exactAborted {
+		t.Errorf("Status = %d, erwartet %d (Budget aufgebraucht)", status, exactAborted)
+	}
+}
@@ -0,0 +1,121 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"           // Für Fehlermeldungen.
+	"os"            // Zum Lesen und Schreiben der Verlaufsdatei.
+	"path/filepath" // Um die Verlaufsdatei neben 'klasse.toml' abzulegen.
+	"strings"       // Zum Ableiten des Dateinamens.
+	"time"          // Für das Datum einer gespeicherten Einteilung.
+
+	"github.com/pelletier/go-toml" // Die Verlaufsdatei ist wie die Konfiguration eine TOML-Datei.
+)
+
+// ############################################################################################
+// recentHistorySessions legt fest, wie viele der letzten gespeicherten Einteilungen
+// 		bei der Bewertung berücksichtigt werden. Ältere Einteilungen zählen nicht mehr.
+const recentHistorySessions = 5
+
+// ############################################################################################
+// historySession ist eine gespeicherte Einteilung in der Verlaufsdatei.
+type historySession struct {
+	Date     string     `toml:"datum"`    // Datum und Uhrzeit der Speicherung.
+	Scenario string     `toml:"szenario"` // Bezeichnung des Szenarios (z.B. "3er-Gruppen").
+	Groups   [][]string `toml:"gruppen"`  // Die Gruppen der Einteilung.
+}
+
+// groupingHistory ist der Inhalt der Verlaufsdatei: alle gespeicherten Einteilungen, die älteste zuerst.
+type groupingHistory struct {
+	Sessions []historySession `toml:"sitzung"`
+}
+
+// ############################################################################################
+// historyPathFor gibt den Pfad der Verlaufsdatei zu einer Konfigurationsdatei zurück.
+// Sie liegt im selben Verzeichnis, z.B. 'klasse.toml' → 'klasse-verlauf.toml'.
+func historyPathFor(configPath string) string {
+	base := strings.TrimSuffix(filepath.Base(configPath), filepath.Ext(configPath))
+	return filepath.Join(filepath.Dir(configPath), base+"-verlauf.toml")
+}
+
+// ############################################################################################
+// loadHistory liest die Verlaufsdatei. Existiert sie noch nicht, ist der Verlauf einfach leer.
+func loadHistory(path string) (*groupingHistory, error) {
+	history := &groupingHistory{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return history, nil // Noch keine Einteilung gespeichert.
+	}
+	if err != nil {
+		return nil, fmt.Errorf("❌ Fehler beim Lesen der Verlaufsdatei '%s': %w", path, err)
+	}
+	if err := toml.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("❌ Fehler beim Parsen der Verlaufsdatei '%s': %w", path, err)
+	}
+	return history, nil
+}
+
+// ############################################################################################
+// saveHistory schreibt den ganzen Verlauf in die Verlaufsdatei.
+func saveHistory(path string, history *groupingHistory) error {
+	data, err := toml.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("❌ Fehler beim Erstellen der Verlaufsdatei: %w", err)
+	}
+	header := "# Verlauf der gespeicherten Einteilungen (wird vom Klassenmischer geschrieben).\n" +
+		"# Paare aus den letzten Einteilungen werden bei neuen Einteilungen möglichst vermieden.\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("❌ Fehler beim Schreiben der Verlaufsdatei '%s': %w", path, err)
+	}
+	return nil
+}
+
+// ############################################################################################
+// addSession hängt eine angenommene Einteilung an den Verlauf an.
+func (h *groupingHistory) addSession(scenarioTitle string, groups [][]string) {
+	h.Sessions = append(h.Sessions, historySession{
+		Date:     time.Now().Format("2006-01-02 15:04"),
+		Scenario: scenarioTitle,
+		Groups:   groups,
+	})
+}
+
+// ############################################################################################
+// recentPairCounts zählt, wie oft jedes Paar in den letzten 'sessions' Einteilungen
+// 		in derselben Gruppe war. Das Ergebnis gilt in beide Richtungen (counts[a][b] == counts[b][a]).
+func (h *groupingHistory) recentPairCounts(sessions int) map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	start := len(h.Sessions) - sessions
+	if start < 0 {
+		start = 0
+	}
+	for _, session := range h.Sessions[start:] {
+		for _, group := range session.Groups {
+			for i, studentA := range group {
+				for _, studentB := range group[i+1:] {
+					for _, pair := range [][2]string{{studentA, studentB}, {studentB, studentA}} {
+						if counts[pair[0]] == nil {
+							counts[pair[0]] = make(map[string]int)
+						}
+						counts[pair[0]][pair[1]]++
+					}
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// ############################################################################################
+// countRepeatedPairs zählt, wie viele Paare einer Einteilung schon in den letzten Einteilungen
+// 		zusammen waren. Ein Paar, das zweimal zusammen war, zählt doppelt.
+func (r *groupRules) countRepeatedPairs(groups [][]string) int {
+	repeats := 0
+	for _, group := range groups {
+		for i, studentA := range group {
+			for _, studentB := range group[i+1:] {
+				repeats += r.repeatCounts[studentA][studentB]
+			}
+		}
+	}
+	return repeats
+}
@@ -0,0 +1,94 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"html/template" // Für die HTML-Datei (maskiert Namen automatisch).
+	"io"            // Zum Schreiben in eine Datei oder nach stdout.
+	"time"          // Für das Datum auf dem Aushang.
+)
+
+// ############################################################################################
+// htmlGroup ist eine Gruppenkarte auf dem Aushang.
+type htmlGroup struct {
+	Name    string   // Name der Gruppe (z.B. "Gruppe 1" oder ein eigener Name aus '-namen').
+	Members []string // Die Schüler der Gruppe.
+}
+
+// htmlSection ist ein Szenario (bzw. eine Runde) auf dem Aushang. Beim Drucken beginnt jedes auf einer neuen Seite.
+type htmlSection struct {
+	Title     string      // Bezeichnung, z.B. "3er-Gruppen".
+	Groups    []htmlGroup // Die Gruppenkarten.
+	Ungrouped []string    // Schüler ohne Gruppe (werden klein darunter angezeigt).
+}
+
+// htmlPage enthält alle Daten für die Vorlage.
+type htmlPage struct {
+	Title    string        // Überschrift ('-titel').
+	Date     string        // Datum der Einteilung.
+	Sections []htmlSection // Alle Szenarien bzw. Runden.
+}
+
+// ############################################################################################
+// htmlTemplate ist die Vorlage für den Aushang: eine einzige Datei ohne externe Abhängigkeiten.
+// Die Karten sind groß genug für einen Beamer; das Druck-Stylesheet entfernt Farben und Schatten
+// 		und verhindert, dass eine Karte über zwei Seiten geteilt wird.
+var htmlTemplate = template.Must(template.New("aushang").Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #222; background: #f4f4f4; }
+  header { display: flex; justify-content: space-between; align-items: baseline; border-bottom: 3px solid #333; margin-bottom: 1.5rem; }
+  h1 { font-size: 2.5rem; margin: 0 0 .5rem 0; }
+  .datum { font-size: 1.4rem; color: #555; }
+  h2 { font-size: 1.8rem; margin: 2rem 0 1rem 0; }
+  .karten { display: grid; grid-template-columns: repeat(auto-fill, minmax(16rem, 1fr)); gap: 1.2rem; }
+  .karte { background: #fff; border: 3px solid #333; border-radius: .8rem; padding: 1rem 1.4rem; box-shadow: 0 .2rem .6rem rgba(0,0,0,.15); }
+  .karte h3 { font-size: 1.6rem; margin: 0 0 .6rem 0; padding-bottom: .4rem; border-bottom: 2px solid #ccc; }
+  .karte ul { list-style: none; margin: 0; padding: 0; }
+  .karte li { font-size: 1.5rem; line-height: 1.6; }
+  .ungruppiert { margin-top: 1rem; font-size: 1.1rem; color: #a00; }
+  @media print {
+    body { margin: 0; background: #fff; }
+    section { page-break-before: always; break-before: page; }
+    section:first-of-type { page-break-before: auto; break-before: auto; }
+    .karte { box-shadow: none; page-break-inside: avoid; break-inside: avoid; }
+  }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{.Title}}</h1>
+  <span class="datum">{{.Date}}</span>
+</header>
+{{range .Sections}}<section>
+  {{if gt (len $.Sections) 1}}<h2>{{.Title}}</h2>{{end}}
+  <div class="karten">
+  {{range .Groups}}  <div class="karte">
+      <h3>{{.Name}}</h3>
+      <ul>{{range .Members}}<li>{{.}}</li>{{end}}</ul>
+    </div>
+  {{end}}</div>
+  {{if .Ungrouped}}<p class="ungruppiert">Ohne Gruppe: {{range $i, $name := .Ungrouped}}{{if $i}}, {{end}}{{$name}}{{end}}</p>{{end}}
+</section>
+{{end}}</body>
+</html>
+`))
+
+// ############################################################################################
+// writeHTML schreibt einen druckbaren Aushang mit einer großen Karte pro Gruppe.
+func writeHTML(w io.Writer, data *exportData) error {
+	page := htmlPage{Title: data.Title, Date: time.Now().Format("02.01.2006")}
+	if page.Title == "" {
+		page.Title = "Gruppeneinteilung"
+	}
+	for _, result := range data.Results {
+		section := htmlSection{Title: result.label(), Ungrouped: result.Ungrouped}
+		for i, group := range result.Groups {
+			section.Groups = append(section.Groups, htmlGroup{Name: groupName(i, data.GroupNames), Members: group})
+		}
+		page.Sections = append(page.Sections, section)
+	}
+	return htmlTemplate.Execute(w, page)
+}
@@ -2,12 +2,14 @@ package main // Deklariert das Paket als 'main', was bedeutet, dass dies ein aus
 
 // ############################################################################################
 import ( // Importiert notwendige Pakete.
+	"flag"         // Zum Einlesen von Kommandozeilen-Optionen (z.B. '-gruppen 7').
 	"fmt"          // Für formatierte Ein- und Ausgabe (z.B. Drucken auf die Konsole, `fmt.Scanln`).
 	"log"          // Für Logging-Ausgaben, besonders nützlich für Debugging-Informationen.
 	"math/rand"    // Für Zufallszahlen-Operationen, hier zum Mischen von Schülerlisten.
 	"os"           // Bietet Schnittstellen zum Betriebssystem (z.B. Dateisystem-Operationen, Beenden des Programms).
 	"path/filepath" // Für plattformunabhängige Pfadmanipulation (z.B. Join, Dir).
 	"sort"         // Zum Sortieren von Slices, hier für das Prüfen symmetrischer Constraints.
+	"strconv"      // Zum Umwandeln der Benutzereingabe in eine Zahl.
 	"strings"      // Für String-Manipulationen (z.B. Join, Contains, HasPrefix).
 
 	"github.com/pelletier/go-toml" // Externe Bibliothek zum Lesen und Schreiben von TOML-Dateien.
@@ -35,12 +37,32 @@ func (e *ConfigFileCreatedError) Error() string {
 // Die Tags `toml:"..."` weisen die go-toml-Bibliothek an, welche Felder in der TOML-Datei
 // 		zu welchen Feldern in dieser Go-Struktur gehören.
 type Config struct {
-	Schuelerliste []string `toml:"schuelerliste"` 
+	Schuelerliste []string `toml:"-"` 
 	// Ein Slice von Strings für die Namen der Schüler.
+	// Wird manuell geparst, da die Einträge auch Tabellen mit Merkmalen sein können.
+	Attributes    map[string]map[string]string `toml:"-"`
+	// Merkmale pro Schüler (z.B. "geschlecht" = "w", "niveau" = "stark") aus der erweiterten Schülerliste.
 	Constraints   map[string][]string `toml:"-"`  
 	// Eine Map für Einschränkungen. Der Tag `toml:"-"` bedeutet,
 	// 		dass dieses Feld von der TOML-Bibliothek ignoriert werden soll.
 	// Constraints werden manuell aus der TOML-Datei geparst.
+	ConflictPairs [][]string `toml:"-"`
+	// Gruppen aus 'konfliktpaare'. Die Konflikte daraus stehen bereits in Constraints, die Gruppen braucht es nur zum Schreiben.
+	Together      map[string][]string `toml:"-"`
+	// Eine Map für Schüler, die zwingend in dieselbe Gruppe müssen (Abschnitt [zusammen]).
+	// Wird wie die Constraints manuell geparst.
+	Avoid         map[string]map[string]int `toml:"-"`
+	// Weiche Einschränkungen: Wer lieber nicht mit wem in eine Gruppe soll, mit Gewicht (Abschnitt [lieber_nicht]).
+	Prefer        map[string]map[string]int `toml:"-"`
+	// Wünsche: Wer gerne mit wem in eine Gruppe möchte, mit Gewicht (Abschnitt [gerne_zusammen]).
+	Balance       []balanceRule `toml:"-"`
+	// Ausgleichsregeln für Merkmale der Schüler (Tabellen [[ausgleich]]).
+	Path          string `toml:"-"`
+	// Pfad, von dem die Konfiguration gelesen wurde (z.B. für die Verlaufsdatei daneben).
+	Report        *validationReport `toml:"-"`
+	// Prüfbericht: alle Probleme beim Lesen der Datei mit Zeile und Spalte (siehe validationReport).
+	Settings      map[string]interface{} `toml:"-"`
+	// Voreinstellungen für die Optionen aus dem Abschnitt [einstellungen] (siehe readSettings).
 }
 
 // ############################################################################################
@@ -109,6 +131,9 @@ func readTomlConfig(filename string) (*Config, error) {
 		// log.Printf("Warnung: Konnte das aktuelle Arbeitsverzeichnis nicht ermitteln: %v. Versuche, die Konfigurationsdatei im Verzeichnis der ausführbaren Datei zu finden.", err)
 	} else {
 		pathInCWD := filepath.Join(cwd, filename) // Kombiniert CWD und Dateinamen zu einem vollständigen Pfad.
+		if filepath.IsAbs(filename) {             // Ein absoluter Pfad (z.B. mit '-datei') wird direkt verwendet.
+			pathInCWD = filename
+		}
 		// log.Printf("Prüfe Konfigurationsdatei im CWD: %s", pathInCWD) // DEBUG-Ausgabe.
 		_, errCWD := os.Stat(pathInCWD)                              // Versucht, Datei-Informationen zu holen (prüft Existenz).
 		if errCWD == nil {                                           // Wenn kein Fehler, existiert die Datei.
@@ -124,7 +149,7 @@ func readTomlConfig(filename string) (*Config, error) {
 
 	// 2. Zweite Priorität (Lesen): Verzeichnis der ausführbaren Datei.
 	// Dieser Pfad ist wichtig für kompilierte Programme, die von einem anderen Ort gestartet werden.
-	if !fileFound { // Nur prüfen, wenn die Datei im CWD noch nicht gefunden wurde.
+	if !fileFound && !filepath.IsAbs(filename) { // Nur prüfen, wenn die Datei im CWD noch nicht gefunden wurde.
 		if execErr != nil { // Wenn der Executable-Pfad selbst nicht ermittelt werden konnte.
 			// log.Printf("Warnung: Konnte den Pfad der ausführbaren Datei nicht ermitteln: %v", execErr)
 		} else {
@@ -154,37 +179,123 @@ func readTomlConfig(filename string) (*Config, error) {
 
 		tree, err := toml.LoadBytes(data) // Parsen der TOML-Daten in eine Baumstruktur.
 		if err != nil {
-			return nil, fmt.Errorf("❌ Fehler beim Parsen der TOML-Datei: %w", err)
+			return nil, syntaxError(finalConfigPath, err) // Mit Zeile und Spalte des Fehlers.
+		}
+
+		// Dateien im alten Format (Konflikte ohne Abschnitt) werden automatisch umgestellt.
+		migrated, changed, err := migrateConfig(string(data), tree)
+		if err != nil {
+			return nil, fmt.Errorf("❌ %s: %w", filepath.Base(finalConfigPath), err)
+		}
+		if changed {
+			if err := saveMigratedConfig(finalConfigPath, data, migrated); err != nil {
+				fmt.Println(err) // Ohne Schreibrechte gilt die umgestellte Fassung nur für diesen Lauf.
+			}
+			data = []byte(migrated)
+			if tree, err = toml.LoadBytes(data); err != nil { // migrateConfig hat die neue Fassung schon geprüft.
+				return nil, syntaxError(finalConfigPath, err)
+			}
 		}
 
 		var config Config // Erstellt eine leere Config-Struktur.
-		// Entpackt die einfachen Felder (wie 'schuelerliste') direkt aus dem TOML-Baum in die Struktur.
-		if err := tree.Unmarshal(&config); err != nil {
-			return nil, fmt.Errorf("❌ Fehler beim Entpacken der Schülerliste aus der TOML-Datei: %w", err)
-		}
-
-		// Constraints müssen manuell aus dem TOML-Baum extrahiert werden,
-		// da sie dynamische Schlüssel haben und nicht direkt mit 'toml:"-"' gemarshallt werden.
-		config.Constraints = make(map[string][]string) // Initialisiert die Constraints-Map.
-		for _, key := range tree.Keys() {             // Iteriert über alle Schlüssel in der TOML-Datei.
-			if key != "schuelerliste" { // Der Schlüssel "schuelerliste" wurde bereits behandelt.
-				val := tree.Get(key) // Holt den Wert für den aktuellen Schlüssel.
-				// Überprüft, ob der Wert ein Slice von Interfaces ist (was einem TOML-Array entspricht).
-				if valSlice, ok := val.([]interface{}); ok {
-					var forbiddenStudents []string
-					for _, item := range valSlice { // Iteriert über die Elemente des Arrays.
-						if studentName, isString := item.(string); isString { // Prüft, ob das Element ein String ist.
-							forbiddenStudents = append(forbiddenStudents, studentName) // Fügt den Namen hinzu.
-						} else {
-							log.Printf("❗️ Warnung: Konflikte für '%s' enthält einen Nicht-String-Wert: %v", key, item)
-						}
-					}
-					config.Constraints[key] = forbiddenStudents // Fügt die Einschränkung zur Map hinzu.
-				} else {
-					log.Printf("❗️ Warnung: Unerwarteter Typ für Schlüssel '%s' in TOML-Datei. Erwartet wurde ein Array, gefunden: %T", key, val)
+		// Alle Probleme beim Lesen werden im Prüfbericht gesammelt und vor der Gruppierung ausgegeben.
+		// Dafür werden zuerst die Fundstellen aller Schlüssel gemerkt.
+		config.Report = newValidationReport(finalConfigPath)
+		recordPositions(config.Report.Positions, tree, "", string(data))
+		for _, sectionName := range []string{conflictsSection, togetherSection, avoidSection, preferSection} {
+			if sectionTree, ok := tree.Get(sectionName).(*toml.Tree); ok {
+				recordPositions(config.Report.Positions, sectionTree, sectionName, string(data))
+			}
+		}
+
+		// Die Schülerliste darf einfache Namen und Tabellen mit Merkmalen enthalten.
+		config.Schuelerliste, config.Attributes = readStudentList(tree, config.Report)
+		// Alternativ kommt die Schülerliste aus einer CSV-Datei der Schulverwaltung (Abschnitt [import]).
+		importedNames, importedAttributes, imported, err := readImportSection(tree, finalConfigPath, config.Report)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Fehler beim Import der Schülerliste: %w", err)
+		}
+		if imported {
+			if len(config.Schuelerliste) > 0 {
+				return nil, fmt.Errorf("❌ 'schuelerliste' und der Abschnitt [%s] können nicht gleichzeitig verwendet werden. Bitte entferne eines von beiden", importSection)
+			}
+			config.Schuelerliste, config.Attributes = importedNames, importedAttributes
+		}
+
+		// Ohne Abschnitt sind nur 'version', 'schuelerliste' und 'konfliktpaare' erlaubt (siehe topLevelKeys).
+		for _, key := range tree.Keys() {
+			if isTopLevelKey(key) || configSections[key] {
+				continue // Diese Schlüssel werden separat behandelt.
+			}
+			val := tree.GetPath([]string{key}) // GetPath, weil Namen wie "Jonas M." Punkte enthalten dürfen.
+			position := config.Report.position("", key)
+			switch val.(type) {
+			case *toml.Tree, []*toml.Tree: // Ein Abschnitt, den es nicht gibt (z.B. Tippfehler wie [zusamen]).
+				message := fmt.Sprintf("Der Abschnitt [%s] ist unbekannt und wird ignoriert", key)
+				if suggestions := suggestNames(key, sortedSectionNames()); len(suggestions) > 0 {
+					message += fmt.Sprintf(". Meintest du [%s]?", suggestions[0])
+				}
+				config.Report.add(position, issueUnknownKey, "%s", message)
+			case []interface{}: // Vermutlich ein Konflikt außerhalb von [konflikte].
+				config.Report.add(position, issueUnknownKey, "'%s' steht ohne Abschnitt und wird ignoriert. Konflikte gehören in den Abschnitt [%s]", key, conflictsSection)
+			default:
+				message := fmt.Sprintf("'%s' ohne Abschnitt ist unbekannt und wird ignoriert. Erlaubt sind: %s", key, strings.Join(topLevelKeys, ", "))
+				if suggestions := suggestNames(key, topLevelKeys); len(suggestions) > 0 {
+					message += fmt.Sprintf(". Meintest du '%s'?", suggestions[0])
+				}
+				config.Report.add(position, issueUnknownKey, "%s", message)
+			}
+		}
+
+		// Der Abschnitt [konflikte] enthält, wer nicht mit wem in eine Gruppe darf.
+		config.Constraints = make(map[string][]string)
+		if section := tree.Get(conflictsSection); section != nil {
+			if sectionTree, ok := section.(*toml.Tree); ok {
+				if sectionTree.Has(conflictPairsKey) { // Sonst würde 'konfliktpaare' als Schüler gelesen.
+					config.Report.add(config.Report.position(conflictsSection, conflictPairsKey), issueUnknownKey, "'%s' muss vor dem ersten Abschnitt stehen, nicht im Abschnitt [%s], und wird ignoriert", conflictPairsKey, conflictsSection)
+					sectionTree.Delete(conflictPairsKey)
+				}
+				config.Constraints = readStringListTable(sectionTree, conflictsSection, config.Report)
+			} else {
+				config.Report.add(config.Report.position("", conflictsSection), issueWrongType, "'%s' muss ein Abschnitt [%s] sein, gefunden: %s", conflictsSection, conflictsSection, describeType(section))
+			}
+		}
+
+		// Mit 'konfliktpaare' wird jeder Konflikt nur einmal angegeben und gilt in beide Richtungen.
+		config.ConflictPairs = readConflictPairs(tree, config.Constraints, config.Report)
+
+		// Der Abschnitt [zusammen] enthält die Schüler, die zwingend in dieselbe Gruppe müssen.
+		config.Together = make(map[string][]string)
+		if section := tree.Get(togetherSection); section != nil {
+			if sectionTree, ok := section.(*toml.Tree); ok {
+				config.Together = readStringListTable(sectionTree, togetherSection, config.Report)
+			} else {
+				config.Report.add(config.Report.position("", togetherSection), issueWrongType, "'%s' muss ein Abschnitt [%s] sein, gefunden: %s", togetherSection, togetherSection, describeType(section))
+			}
+		}
+
+		// Die Abschnitte [lieber_nicht] und [gerne_zusammen] enthalten weiche Einschränkungen mit Gewichten.
+		config.Avoid = make(map[string]map[string]int)
+		config.Prefer = make(map[string]map[string]int)
+		for sectionName, target := range map[string]map[string]map[string]int{avoidSection: config.Avoid, preferSection: config.Prefer} {
+			section := tree.Get(sectionName)
+			if section == nil {
+				continue // Abschnitt ist optional.
+			}
+			if sectionTree, ok := section.(*toml.Tree); ok {
+				for student, weights := range readWeightedTable(sectionTree, sectionName, config.Report) {
+					target[student] = weights
 				}
+			} else {
+				config.Report.add(config.Report.position("", sectionName), issueWrongType, "'%s' muss ein Abschnitt [%s] sein, gefunden: %s", sectionName, sectionName, describeType(section))
 			}
 		}
+
+		// Die Tabellen [[ausgleich]] enthalten Regeln, wie Merkmale auf die Gruppen verteilt werden.
+		config.Balance = readBalanceRules(tree, config.Report)
+		// Der Abschnitt [einstellungen] enthält Voreinstellungen für die Optionen (z.B. groesse = 3).
+		config.Settings = readSettings(tree, config.Report)
+		config.Path = finalConfigPath
 		return &config, nil // Gibt die befüllte Konfiguration zurück.
 	}
 
@@ -192,9 +303,12 @@ func readTomlConfig(filename string) (*Config, error) {
 	// Die Priorität beim Erstellen hängt davon ab, wie das Programm gestartet wurde.
 	var pathToCreate string // Der Pfad, an dem die Musterdatei erstellt werden soll.
 
+	// 0. Ein absoluter Pfad wird immer so verwendet, wie er angegeben wurde.
 	// 1. Priorität (Erstellen): Wenn es 'go run' ist UND CWD ermittelbar.
 	// In diesem Fall ist das CWD der logischste Ort für die Erstellung der Datei.
-	if isGoRunBuild && getwdErr == nil {
+	if filepath.IsAbs(filename) {
+		pathToCreate = filename
+	} else if isGoRunBuild && getwdErr == nil {
 		pathToCreate = filepath.Join(currentWorkingDir, filename)
 		// log.Printf("Logik: 'go run' erkannt, erstelle Musterdatei im aktuellen Arbeitsverzeichnis (CWD): '%s'", pathToCreate)
 	} else if execErr == nil { // 2. Priorität (Erstellen): Ansonsten, wenn Executable-Pfad bekannt (typisch für kompilierte Apps).
@@ -215,35 +329,174 @@ func readTomlConfig(filename string) (*Config, error) {
 	fmt.Printf("❗️ '%s' nicht gefunden. \nErstelle eine Musterdatei.", finalConfigPath)
 	// log.Printf("Erstelle Muster-Konfigurationsdatei unter: %s", finalConfigPath) // DEBUG
 
+	if err := writeSampleConfig(finalConfigPath); err != nil {
+		return nil, err
+	}
+	// Gibt den speziellen Fehler zurück, um anzuzeigen, dass eine Datei erstellt wurde.
+	return nil, &ConfigFileCreatedError{FilePath: finalConfigPath}
+}
+
+// ############################################################################################
+// writeSampleConfig schreibt eine Musterdatei mit Beispiel-Schülern und Beispielen
+// 		für alle Abschnitte nach 'path' (beim ersten Start und mit dem Befehl 'erstellen').
+func writeSampleConfig(path string) error {
 	// Standard-Schülerliste und Beispiel-Constraints für die Musterdatei.
-	defaultSchuelerliste := []string{
-		"Schueler 1", "Schueler 2", "Schueler 3", "Schueler 4", "Schueler 5",
-		"Schueler 6", "Schueler 7", "Schueler 8", "Schueler 9", "Schueler 10",
+	sample := &Config{
+		Schuelerliste: []string{
+			"Schueler 1", "Schueler 2", "Schueler 3", "Schueler 4", "Schueler 5",
+			"Schueler 6", "Schueler 7", "Schueler 8", "Schueler 9", "Schueler 10",
+		},
+		Constraints: map[string][]string{
+			"Schueler 1": {"Schueler 2", "Schueler 3"},
+			"Schueler 2": {"Schueler 1"}, // Symmetrische Einschränkung als Beispiel.
+			"Schueler 3": {"Schueler 1"},
+		},
+		Together: map[string][]string{
+			"Schueler 4": {"Schueler 5"},
+			"Schueler 5": {"Schueler 4"}, // Auch Pflichtpartner werden symmetrisch angegeben.
+		},
+		Avoid: map[string]map[string]int{
+			"Schueler 6": {"Schueler 7": 1}, // Ohne Gewicht zählt ein Eintrag einfach (Gewicht 1).
+		},
+		Prefer: map[string]map[string]int{
+			"Schueler 8": {"Schueler 9": 2},
+		},
 	}
-	sampleConstraints := map[string][]string{
-		"Schueler 1": {"Schueler 2", "Schueler 3"},
-		"Schueler 2": {"Schueler 1"}, // Symmetrische Einschränkung als Beispiel.
-		"Schueler 3": {"Schueler 1"},
+
+	// Schreibt den erstellten Inhalt in die Datei.
+	err := os.WriteFile(path, []byte(formatConfigFile(sample, nil)), 0644) // 0644 sind Dateiberechtigungen (Lesen/Schreiben für Besitzer, nur Lesen für andere).
+	if err != nil {
+		return fmt.Errorf("❌ Fehler beim Schreiben der Muster-Konfigurationsdatei '%s': %w", path, err)
 	}
+	return nil
+}
 
-	// Erstellt den Inhalt der Musterdatei als String.
-	var sb strings.Builder                                                                    // Effizienter String-Builder.
-	sb.WriteString(fmt.Sprintf("schuelerliste = %s\n\n", formatStringSliceToTomlArray(defaultSchuelerliste))) // Schülerliste als TOML-Array.
-	sb.WriteString("# Hier kannst du Einschränkungen definieren, wer nicht mit wem in eine Gruppe soll.\n")
+// formatConfigFile erstellt den Inhalt einer Konfigurationsdatei im aktuellen Format (siehe schema.go)
+// 		mit kurzen Erklärungen zu jedem Abschnitt (für die Musterdatei und den Befehl 'bearbeiten').
+// 'preserved' enthält Abschnitte, die unverändert übernommen werden (Name → Text, z.B. [import]).
+// Ohne Schülerliste (z.B. bei [import]) wird keine 'schuelerliste' geschrieben.
+func formatConfigFile(config *Config, preserved map[string]string) string {
+	var sb strings.Builder                     // Effizienter String-Builder.
+	sb.WriteString(schemaVersionLine() + "\n") // Version des Dateiformats.
+	if len(config.Schuelerliste) > 0 {
+		sb.WriteString(fmt.Sprintf("schuelerliste = %s\n\n", formatStudentList(config.Schuelerliste, config.Attributes))) // Schülerliste als TOML-Array.
+	}
+	sb.WriteString("# Jeden Konflikt nur einmal angeben (gilt in beide Richtungen), muss vor dem ersten Abschnitt stehen:\n")
+	sb.WriteString("# konfliktpaare = [[\"Schueler A\", \"Schueler B\"]]\n")
+	if len(config.ConflictPairs) > 0 {
+		sb.WriteString(fmt.Sprintf("%s = %s\n", conflictPairsKey, formatConflictPairs(config.ConflictPairs)))
+	}
+	sb.WriteString("\n# Bitte passe die 'schuelerliste' und die Abschnitte unten an deine Bedürfnisse an.\n")
+	if text, found := preserved[settingsSection]; found {
+		sb.WriteString("\n" + text)
+	} else {
+		sb.WriteString("\n# Voreinstellungen: gelten wie die gleichnamigen Optionen, wenn diese beim Aufruf fehlen.\n")
+		sb.WriteString(fmt.Sprintf("[%s]\n", settingsSection))
+		sb.WriteString("# groesse = 3       # nur 3er-Gruppen bilden (wie '-groesse 3')\n")
+		sb.WriteString("# format = \"html\"   # immer einen Aushang erstellen (wie '-format html')\n")
+	}
+	sb.WriteString("\n# Im Abschnitt [konflikte] kannst du festlegen, wer nicht mit wem in eine Gruppe soll.\n")
 	sb.WriteString("# Beispiel: \"Schueler A\" = [\"Schueler B\", \"Schueler C\"]\n")
 	sb.WriteString("# Achte auf symmetrische Einschränkungen! Wenn \"X\" nicht mit \"Y\" soll, muss auch \"Y\" nicht mit \"X\" wollen.\n")
-	for student, forbidden := range sampleConstraints { // Fügt die Beispiel-Constraints hinzu.
-		sb.WriteString(fmt.Sprintf("%q = %s\n", student, formatStringSliceToTomlArray(forbidden)))
+	sb.WriteString("# Der Befehl 'symmetrisch' ergänzt fehlende Gegenrichtungen automatisch.\n")
+	sb.WriteString(fmt.Sprintf("[%s]\n", conflictsSection))
+	for _, student := range sortedKeys(config.Constraints) { // Fügt die Constraints hinzu.
+		if len(config.Constraints[student]) > 0 {
+			sb.WriteString(fmt.Sprintf("%q = %s\n", student, formatStringSliceToTomlArray(config.Constraints[student])))
+		}
+	}
+	sb.WriteString("\n# Im Abschnitt [zusammen] kannst du festlegen, wer zwingend mit wem in eine Gruppe muss (z.B. Lernbegleitung).\n")
+	sb.WriteString(fmt.Sprintf("[%s]\n", togetherSection))
+	for _, student := range sortedKeys(config.Together) { // Fügt die Pflichtpartner hinzu.
+		if len(config.Together[student]) > 0 {
+			sb.WriteString(fmt.Sprintf("%q = %s\n", student, formatStringSliceToTomlArray(config.Together[student])))
+		}
+	}
+	sb.WriteString("\n# Weiche Einschränkungen sind Wünsche, keine festen Regeln. Sie werden möglichst erfüllt.\n")
+	sb.WriteString("# [lieber_nicht]: wer lieber nicht zusammen soll; [gerne_zusammen]: wer gerne zusammen arbeiten möchte.\n")
+	sb.WriteString("# Mit Gewicht: \"Schueler A\" = { \"Schueler B\" = 3 } (je höher, desto wichtiger).\n")
+	sb.WriteString(fmt.Sprintf("[%s]\n", avoidSection))
+	for _, student := range sortedKeys(config.Avoid) { // Fügt die Wünsche hinzu.
+		sb.WriteString(fmt.Sprintf("%q = %s\n", student, formatWeights(config.Avoid[student])))
+	}
+	sb.WriteString(fmt.Sprintf("\n[%s]\n", preferSection))
+	for _, student := range sortedKeys(config.Prefer) {
+		sb.WriteString(fmt.Sprintf("%q = %s\n", student, formatWeights(config.Prefer[student])))
 	}
-	sb.WriteString("\n# Bitte passe die 'schuelerliste' und 'Konflikte' oben an deine Bedürfnisse an.\n")
 
-	// Schreibt den erstellten Inhalt in die Datei.
-	err = os.WriteFile(finalConfigPath, []byte(sb.String()), 0644) // 0644 sind Dateiberechtigungen (Lesen/Schreiben für Besitzer, nur Lesen für andere).
-	if err != nil {
-		return nil, fmt.Errorf("❌ Fehler beim Schreiben der Muster-Konfigurationsdatei '%s': %w", finalConfigPath, err)
+	// Alle übrigen Abschnitte (z.B. [import] und [[ausgleich]]) unverändert am Ende.
+	var names []string
+	for name := range preserved {
+		if name != settingsSection {
+			names = append(names, name)
+		}
 	}
-	// Gibt den speziellen Fehler zurück, um anzuzeigen, dass eine Datei erstellt wurde.
-	return nil, &ConfigFileCreatedError{FilePath: finalConfigPath}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString("\n" + preserved[name])
+	}
+	return sb.String()
+}
+
+// formatStudentList schreibt die Schülerliste als TOML-Array. Schüler mit Merkmalen werden als Tabelle geschrieben,
+// 		dann steht jeder Eintrag auf einer eigenen Zeile.
+func formatStudentList(names []string, attributes map[string]map[string]string) string {
+	hasAttributes := false
+	for _, name := range names {
+		if len(attributes[name]) > 0 {
+			hasAttributes = true
+		}
+	}
+	if !hasAttributes {
+		return formatStringSliceToTomlArray(names) // Wie bisher: alle Namen auf einer Zeile.
+	}
+	var sb strings.Builder
+	sb.WriteString("[\n")
+	for _, name := range names {
+		if len(attributes[name]) == 0 {
+			sb.WriteString(fmt.Sprintf("    %q,\n", name))
+			continue
+		}
+		fields := []string{fmt.Sprintf("name = %q", name)}
+		for _, key := range sortedKeys(attributes[name]) {
+			fields = append(fields, fmt.Sprintf("%s = %q", formatTomlKey(key), attributes[name][key]))
+		}
+		sb.WriteString(fmt.Sprintf("    { %s },\n", strings.Join(fields, ", ")))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// formatWeights schreibt die Wünsche eines Schülers: ohne eigene Gewichte als Array, sonst als Tabelle.
+func formatWeights(weights map[string]int) string {
+	names := make([]string, 0, len(weights))
+	allOne := true
+	for name, weight := range weights {
+		names = append(names, name)
+		allOne = allOne && weight == 1
+	}
+	sort.Strings(names)
+	if allOne {
+		return formatStringSliceToTomlArray(names)
+	}
+	fields := make([]string, len(names))
+	for i, name := range names {
+		fields[i] = fmt.Sprintf("%q = %d", name, weights[name])
+	}
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+
+// formatTomlKey setzt einen Schlüssel nur dann in Anführungszeichen, wenn TOML es verlangt (z.B. bei Leerzeichen).
+func formatTomlKey(key string) string {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return fmt.Sprintf("%q", key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
 }
 
 // ############################################################################################
@@ -258,38 +511,388 @@ func formatStringSliceToTomlArray(slice []string) string {
 }
 
 // ############################################################################################
-// isValidGroup überprüft, ob eine gegebene Gruppe von Schülern gültig ist,
-// basierend auf den definierten Einschränkungen (Constraints).
-// Eine Gruppe ist ungültig, wenn Schüler in ihr sind, die nicht zusammenarbeiten dürfen.
-func isValidGroup(group []string, constraints map[string][]string) bool {
-	// Iteriert über jedes mögliche Paar von Schülern innerhalb der Gruppe.
-	for i, studentA := range group {
-		forbiddenList, exists := constraints[studentA] // Holt die Liste der Schüler, mit denen studentA nicht zusammenarbeiten darf.
-		if !exists {
-			continue // Wenn studentA keine Einschränkungen hat, überspringe ihn.
+// Namen der Abschnitte in 'klasse.toml' (ab Version 2, siehe schema.go).
+const (
+	conflictsSection = "konflikte"      // Schüler, die nicht zusammen in eine Gruppe dürfen.
+	togetherSection  = "zusammen"       // Schüler, die zwingend zusammen in eine Gruppe müssen.
+	avoidSection     = "lieber_nicht"   // Weiche Einschränkung: lieber nicht zusammen.
+	preferSection    = "gerne_zusammen" // Wunsch: gerne zusammen.
+	settingsSection  = "einstellungen"  // Voreinstellungen für die Optionen (z.B. groesse = 3).
+)
+
+// configSections enthält alle Abschnitte, die das Programm kennt.
+var configSections = map[string]bool{
+	conflictsSection: true,
+	togetherSection:  true,
+	avoidSection:     true,
+	preferSection:    true,
+	settingsSection:  true,
+	balanceSection:   true,
+	importSection:    true,
+}
+
+// sortedSectionNames gibt die Namen aller Abschnitte zurück (für Vorschläge bei Tippfehlern).
+func sortedSectionNames() []string {
+	var names []string
+	for name := range configSections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ############################################################################################
+// readStudentList liest die 'schuelerliste' ein. Jeder Eintrag ist entweder ein einfacher Name
+// 		oder eine Tabelle mit dem Namen und beliebigen Merkmalen, z.B.:
+// 		schuelerliste = ["Ben", { name = "Anna", geschlecht = "w", niveau = "stark" }]
+// Merkmale werden als Text gespeichert; Listen werden mit Komma verbunden.
+// Probleme (leere Namen, falsche Typen, auch der ganzen Liste) kommen in den Prüfbericht.
+func readStudentList(tree *toml.Tree, report *validationReport) ([]string, map[string]map[string]string) {
+	var names []string
+	attributes := make(map[string]map[string]string)
+
+	val := tree.Get("schuelerliste")
+	if val == nil {
+		return names, attributes // Keine Schülerliste: leere Klasse.
+	}
+	var entries []interface{}
+	switch list := val.(type) {
+	case []interface{}: // Namen (eventuell gemischt mit Tabellen).
+		entries = list
+	case []*toml.Tree: // Nur Tabellen.
+		for _, table := range list {
+			entries = append(entries, table)
 		}
+	default:
+		report.add(report.position("", "schuelerliste"), issueWrongType, "'schuelerliste' muss ein Array sein, gefunden: %s", describeType(val))
+		return names, attributes
+	}
 
-		for j, studentB := range group {
-			if i == j {
-				continue // Überspringe den Vergleich eines Schülers mit sich selbst.
+	position := report.position("", "schuelerliste") // Einträge im Array haben keine eigene Fundstelle.
+	for i, entry := range entries {
+		switch student := entry.(type) {
+		case string: // Einfacher Name ohne Merkmale.
+			if strings.TrimSpace(student) == "" {
+				report.add(position, issueEmptyName, "Eintrag %d in 'schuelerliste' ist leer und wird ignoriert", i+1)
+				continue
 			}
+			names = append(names, student)
+		case *toml.Tree: // Tabelle mit Name und Merkmalen.
+			name, isString := student.Get("name").(string)
+			if !isString {
+				report.add(position, issueWrongType, "Eintrag %d in 'schuelerliste' braucht einen 'name' in Anführungszeichen, gefunden: %s", i+1, describeType(student.Get("name")))
+				continue
+			}
+			if strings.TrimSpace(name) == "" {
+				report.add(position, issueEmptyName, "Eintrag %d in 'schuelerliste' hat einen leeren 'name' und wird ignoriert", i+1)
+				continue
+			}
+			names = append(names, name)
+			studentAttributes := make(map[string]string)
+			for _, key := range student.Keys() {
+				if key != "name" {
+					studentAttributes[key] = formatAttributeValue(student.Get(key))
+				}
+			}
+			attributes[name] = studentAttributes
+		default:
+			report.add(position, issueWrongType, "Eintrag %d in 'schuelerliste' muss ein Name oder eine Tabelle sein, gefunden: %s", i+1, describeType(entry))
+		}
+	}
+	return names, attributes
+}
+
+// formatAttributeValue wandelt den Wert eines Merkmals in Text um (z.B. 12 → "12", ["a", "b"] → "a, b").
+func formatAttributeValue(value interface{}) string {
+	if list, isList := value.([]interface{}); isList {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(value)
+}
 
-			// Prüft, ob studentB in der Verbotsliste von studentA ist.
-			for _, forbiddenStudent := range forbiddenList {
-				if studentB == forbiddenStudent {
-					return false // Ungültige Gruppe gefunden!
+// ############################################################################################
+// readStringListTable liest einen TOML-Abschnitt der Form "Name" = ["Name A", "Name B"]
+// 		in eine Map ein. Falsche Typen werden wie bei den Constraints im Prüfbericht gemeldet.
+func readStringListTable(tree *toml.Tree, sectionName string, report *validationReport) map[string][]string {
+	result := make(map[string][]string)
+	for _, key := range tree.Keys() {
+		val := tree.GetPath([]string{key})
+		position := report.position(sectionName, key)
+		if strings.TrimSpace(key) == "" {
+			report.add(position, issueEmptyName, "Ein leerer Name als Schlüssel im Abschnitt [%s] wird ignoriert", sectionName)
+			continue
+		}
+		valSlice, ok := val.([]interface{})
+		if !ok {
+			report.add(position, issueWrongType, "'%s' im Abschnitt [%s] muss ein Array sein (z.B. [\"Name\"]), gefunden: %s", key, sectionName, describeType(val))
+			continue
+		}
+		result[key] = readNameList(valSlice, key, fmt.Sprintf("'%s' im Abschnitt [%s]", key, sectionName), position, report)
+	}
+	return result
+}
+
+// ############################################################################################
+// readWeightedTable liest einen Abschnitt mit weichen Einschränkungen ein.
+// Erlaubt sind zwei Formen:
+// 		"Name" = ["Name A", "Name B"]          (jeder Eintrag hat Gewicht 1)
+// 		"Name" = { "Name A" = 3, "Name B" = 1 } (eigene Gewichte, ganze Zahlen größer 0)
+func readWeightedTable(tree *toml.Tree, sectionName string, report *validationReport) map[string]map[string]int {
+	result := make(map[string]map[string]int)
+	for _, key := range tree.Keys() {
+		weights := make(map[string]int)
+		position := report.position(sectionName, key)
+		where := fmt.Sprintf("'%s' im Abschnitt [%s]", key, sectionName)
+		switch val := tree.GetPath([]string{key}).(type) {
+		case []interface{}: // Liste ohne Gewichte.
+			for _, name := range readNameList(val, key, where, position, report) {
+				weights[name] = 1
+			}
+		case *toml.Tree: // Tabelle mit Gewichten.
+			for _, name := range val.Keys() {
+				weight, isInt := val.GetPath([]string{name}).(int64)
+				switch {
+				case !isInt || weight <= 0:
+					report.add(position, issueWrongType, "%s: Gewicht für '%s' muss eine ganze Zahl größer 0 sein, gefunden: %s", where, name, describeType(val.GetPath([]string{name})))
+				case strings.TrimSpace(name) == "":
+					report.add(position, issueEmptyName, "%s: ein Name ist leer", where)
+				case name == key:
+					report.add(position, issueSelfConflict, "%s: '%s' steht in der eigenen Liste und wird ignoriert", where, name)
+				default:
+					weights[name] = int(weight)
 				}
 			}
-			// Zusätzlich prüft man die umgekehrte Richtung für Symmetrie (redundant, wenn Symmetrie geprüft wurde, aber sicherheitshalber).
-			forbiddenListB, existsB := constraints[studentB]
-			if existsB {
-				for _, forbiddenStudent := range forbiddenListB {
-					if studentA == forbiddenStudent {
-						return false // Ungültige Gruppe gefunden!
-					}
+		default:
+			report.add(position, issueWrongType, "%s muss ein Array oder eine Tabelle sein (z.B. { \"Name\" = 2 }), gefunden: %s", where, describeType(val))
+			continue
+		}
+		result[key] = weights
+	}
+	return result
+}
+
+// ############################################################################################
+// sortedKeys gibt die Schlüssel einer Map sortiert zurück, damit Ausgaben und Dateien stabil bleiben.
+// Funktioniert für alle Maps mit Namen als Schlüssel (Konflikte, Gewichte, Merkmale).
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ############################################################################################
+// groupRules fasst alle Regeln zusammen, die bei der Gruppenbildung gelten.
+// Sie wird einmal aus der Konfiguration erstellt (newGroupRules) und an alle Gruppierungs-Funktionen übergeben.
+type groupRules struct {
+	Constraints     map[string][]string          // Wer nicht mit wem in eine Gruppe darf.
+	Together        map[string][]string          // Wer zwingend mit wem in eine Gruppe muss.
+	units           map[string][]string          // Für jeden Schüler alle Schüler, die mit ihm zusammen sein müssen (inkl. ihm selbst).
+	pairWeights     map[string]map[string]int    // Gewicht jedes Paares aus den weichen Einschränkungen (positiv = erwünscht).
+	attributes      map[string]map[string]string // Merkmale pro Schüler (siehe attribute).
+	balance         []balanceRule                // Ausgleichsregeln für Merkmale.
+	repeatCounts    map[string]map[string]int    // Wie oft jedes Paar in den letzten Einteilungen zusammen war (siehe groupingHistory).
+	attributeValues map[string]map[string]bool   // Für jedes Merkmal alle Werte, die in der Klasse vorkommen.
+	maxScore        int                          // Bestmögliche Bewertung: alle Wünsche erfüllt, kein "lieber nicht" verletzt.
+}
+
+// newGroupRules erstellt die Regeln für eine Klasse aus der Konfiguration.
+// Pflichtpartner gelten in beide Richtungen und transitiv: Muss A mit B und B mit C,
+// 		dann bilden A, B und C eine Einheit, die nur gemeinsam eingeteilt wird.
+// Namen, die nicht in der Schülerliste vorkommen, werden dabei ignoriert.
+func newGroupRules(config *Config) *groupRules {
+	students, constraints, together := config.Schuelerliste, config.Constraints, config.Together
+	inClass := make(map[string]bool)
+	for _, student := range students {
+		inClass[student] = true
+	}
+
+	// Ungerichteter Graph der Pflichtpartner.
+	neighbours := make(map[string][]string)
+	for student, partners := range together {
+		for _, partner := range partners {
+			if inClass[student] && inClass[partner] && student != partner {
+				neighbours[student] = append(neighbours[student], partner)
+				neighbours[partner] = append(neighbours[partner], student)
+			}
+		}
+	}
+
+	// Zusammenhangskomponenten bilden die Einheiten (Reihenfolge wie in der Schülerliste).
+	units := make(map[string][]string)
+	for _, student := range students {
+		if _, done := units[student]; done {
+			continue
+		}
+		unit := []string{student}
+		visited := map[string]bool{student: true}
+		for i := 0; i < len(unit); i++ {
+			for _, partner := range neighbours[unit[i]] {
+				if !visited[partner] {
+					visited[partner] = true
+					unit = append(unit, partner)
 				}
 			}
 		}
+		for _, member := range unit {
+			units[member] = unit
+		}
+	}
+
+	rules := &groupRules{Constraints: constraints, Together: together, units: units,
+		attributes: config.Attributes, balance: config.Balance}
+	rules.attributeValues = make(map[string]map[string]bool)
+	for _, student := range students {
+		for key, value := range config.Attributes[student] {
+			if rules.attributeValues[key] == nil {
+				rules.attributeValues[key] = make(map[string]bool)
+			}
+			rules.attributeValues[key][value] = true
+		}
+	}
+	rules.pairWeights = make(map[string]map[string]int)
+	for student, weights := range config.Avoid {
+		for other, weight := range weights {
+			rules.addPairWeight(student, other, -weight, inClass)
+		}
+	}
+	for student, weights := range config.Prefer {
+		for other, weight := range weights {
+			rules.addPairWeight(student, other, weight, inClass)
+		}
+	}
+	for student, weights := range rules.pairWeights { // Jedes Paar nur einmal zählen.
+		for other, weight := range weights {
+			if student < other && weight > 0 {
+				rules.maxScore += weight
+			}
+		}
+	}
+	return rules
+}
+
+// addPairWeight addiert ein Gewicht für das Paar (a, b) in beide Richtungen.
+// Wünschen sich beide gegenseitig, zählt der Wunsch also doppelt.
+func (r *groupRules) addPairWeight(studentA, studentB string, weight int, inClass map[string]bool) {
+	if studentA == studentB || !inClass[studentA] || !inClass[studentB] {
+		return
+	}
+	for _, pair := range [][2]string{{studentA, studentB}, {studentB, studentA}} {
+		if r.pairWeights[pair[0]] == nil {
+			r.pairWeights[pair[0]] = make(map[string]int)
+		}
+		r.pairWeights[pair[0]][pair[1]] += weight
+	}
+}
+
+// attribute gibt ein Merkmal eines Schülers zurück (z.B. attribute("Anna", "geschlecht") = "w").
+// Fehlt das Merkmal, ist das Ergebnis ein leerer String.
+func (r *groupRules) attribute(student string, key string) string {
+	return r.attributes[student][key]
+}
+
+// hasSoftConstraints gibt an, ob es weiche Einschränkungen gibt, nach denen bewertet wird.
+func (r *groupRules) hasSoftConstraints() bool {
+	if len(r.pairWeights) > 0 || len(r.repeatCounts) > 0 {
+		return true
+	}
+	for _, rule := range r.balance {
+		if rule.Mix {
+			return true
+		}
+	}
+	return false
+}
+
+// conflict prüft, ob zwei Schüler nicht in dieselbe Gruppe dürfen (in beide Richtungen).
+func (r *groupRules) conflict(studentA, studentB string) bool {
+	for _, forbidden := range r.Constraints[studentA] {
+		if forbidden == studentB {
+			return true
+		}
+	}
+	for _, forbidden := range r.Constraints[studentB] {
+		if forbidden == studentA {
+			return true
+		}
+	}
+	return false
+}
+
+// unitOf gibt alle Schüler zurück, die zwingend mit 'student' in dieselbe Gruppe müssen (inkl. ihm selbst).
+func (r *groupRules) unitOf(student string) []string {
+	if unit, exists := r.units[student]; exists {
+		return unit
+	}
+	return []string{student}
+}
+
+// splitIntoUnits teilt eine Schülerliste in Einheiten von Pflichtpartnern auf.
+// Die Reihenfolge richtet sich nach dem ersten Auftreten in 'students';
+// 		Einheiten, von denen nicht alle Mitglieder in 'students' stehen, werden ausgelassen.
+func (r *groupRules) splitIntoUnits(students []string) [][]string {
+	available := make(map[string]bool)
+	for _, student := range students {
+		available[student] = true
+	}
+	var result [][]string
+	done := make(map[string]bool)
+	for _, student := range students {
+		if done[student] {
+			continue
+		}
+		unit := r.unitOf(student)
+		complete := true
+		for _, member := range unit {
+			done[member] = true
+			if !available[member] {
+				complete = false
+			}
+		}
+		if complete {
+			result = append(result, unit)
+		}
+	}
+	return result
+}
+
+// ############################################################################################
+// isValidGroup überprüft, ob eine gegebene Gruppe von Schülern gültig ist,
+// basierend auf den definierten Regeln (Constraints, Pflichtpartner und feste Ausgleichsregeln).
+// Eine Gruppe ist ungültig, wenn Schüler in ihr sind, die nicht zusammenarbeiten dürfen,
+// 		wenn eine Ausgleichsregel verletzt ist oder wenn ein Pflichtpartner eines Mitglieds fehlt.
+func isValidGroup(group []string, rules *groupRules) bool {
+	// Iteriert über jedes mögliche Paar von Schülern innerhalb der Gruppe.
+	for i, studentA := range group {
+		for j := i + 1; j < len(group); j++ {
+			if rules.conflict(studentA, group[j]) { // Prüft beide Richtungen, auch bei unsymmetrischen Constraints.
+				return false // Ungültige Gruppe gefunden!
+			}
+		}
+	}
+
+	// Feste Ausgleichsregeln (z.B. höchstens 1 starker Schüler pro Gruppe).
+	if rules.exceedsBalanceLimits(group) {
+		return false
+	}
+
+	// Jeder Pflichtpartner eines Mitglieds muss ebenfalls in der Gruppe sein.
+	inGroup := make(map[string]bool)
+	for _, student := range group {
+		inGroup[student] = true
+	}
+	for _, student := range group {
+		for _, partner := range rules.unitOf(student) {
+			if !inGroup[partner] {
+				return false // Pflichtpartner fehlt.
+			}
+		}
 	}
 	return true // Wenn keine Konflikte gefunden wurden, ist die Gruppe gültig.
 }
@@ -298,7 +901,7 @@ func isValidGroup(group []string, constraints map[string][]string) bool {
 // attemptToFormGroupsOfSize versucht, so viele Gruppen einer bestimmten Zielgröße wie möglich zu bilden.
 // Es wählt zufällig Schüler aus und prüft, ob die Gruppe gültig ist.
 func attemptToFormGroupsOfSize(targetSize int, studentsPool []string, usedStudents map[string]bool,
-	existingGroups [][]string, constraints map[string][]string) ([][]string, map[string]bool) {
+	existingGroups [][]string, rules *groupRules, rng *rand.Rand) ([][]string, map[string]bool) {
 
 	// Erstellt Kopien der aktuellen Gruppen und verwendeten Schüler, um Änderungen rückgängig machen zu können,
 	// falls eine Iteration nicht zu besseren Ergebnissen führt.
@@ -322,17 +925,16 @@ func attemptToFormGroupsOfSize(targetSize int, studentsPool []string, usedStuden
 		}
 
 		// Mischt die Liste der verfügbaren Schüler, um zufällige Gruppen zu bilden.
-		rand.Shuffle(len(availableStudents), func(i, j int) {
+		rng.Shuffle(len(availableStudents), func(i, j int) {
 			availableStudents[i], availableStudents[j] = availableStudents[j], availableStudents[i]
 		})
 
 		foundGroupInThisIteration := false
 		if len(availableStudents) >= targetSize {
-			// Wählt die ersten 'targetSize' Schüler für eine potenzielle Gruppe.
-			potentialGroup := make([]string, targetSize)
-			copy(potentialGroup, availableStudents[:targetSize])
+			// Wählt die ersten 'targetSize' Schüler (samt ihren Pflichtpartnern) für eine potenzielle Gruppe.
+			potentialGroup := pickUnitsForGroup(availableStudents, targetSize, rules)
 
-			if isValidGroup(potentialGroup, constraints) { // Prüft, ob die Gruppe gültig ist.
+			if len(potentialGroup) == targetSize && isValidGroup(potentialGroup, rules) { // Prüft, ob die Gruppe gültig ist.
 				currentGroups = append(currentGroups, potentialGroup) // Fügt die Gruppe hinzu.
 				for _, s := range potentialGroup {
 					currentUsedStudents[s] = true // Markiert die Schüler als verwendet.
@@ -349,10 +951,51 @@ func attemptToFormGroupsOfSize(targetSize int, studentsPool []string, usedStuden
 }
 
 // ############################################################################################
-// tryIntegrateIntoExistingGroup versucht, einen einzelnen "einsamen" Schüler
-// in eine bestehende Gruppe zu integrieren, um eine neue Zielgröße zu erreichen.
-func tryIntegrateIntoExistingGroup(lonelyStudent string, targetGroupSize int, newGroupSize int,
-	existingGroups [][]string, usedStudents map[string]bool, constraints map[string][]string) (bool, [][]string, map[string]bool) {
+// pickUnitsForGroup wählt der Reihe nach Schüler aus 'availableStudents' aus, bis 'size' erreicht ist.
+// Pflichtpartner werden immer gemeinsam gewählt; passt eine Einheit nicht mehr hinein, wird sie übersprungen.
+// Ohne Pflichtpartner sind das einfach die ersten 'size' Schüler.
+func pickUnitsForGroup(availableStudents []string, size int, rules *groupRules) []string {
+	available := make(map[string]bool)
+	for _, student := range availableStudents {
+		available[student] = true
+	}
+
+	var picked []string
+	inPicked := make(map[string]bool)
+	for _, student := range availableStudents {
+		if len(picked) == size {
+			break
+		}
+		if inPicked[student] {
+			continue
+		}
+		unit := rules.unitOf(student)
+		if len(picked)+len(unit) > size {
+			continue // Einheit zu groß für den restlichen Platz.
+		}
+		complete := true
+		for _, member := range unit {
+			if !available[member] {
+				complete = false // Ein Pflichtpartner ist bereits in einer anderen Gruppe.
+			}
+		}
+		if !complete {
+			continue
+		}
+		for _, member := range unit {
+			picked = append(picked, member)
+			inPicked[member] = true
+		}
+	}
+	return picked
+}
+
+// ############################################################################################
+// tryIntegrateIntoExistingGroup versucht, "einsame" Schüler (ein einzelner Schüler
+// 		oder eine Einheit von Pflichtpartnern) gemeinsam in eine bestehende Gruppe
+// 		zu integrieren, um eine neue Zielgröße zu erreichen.
+func tryIntegrateIntoExistingGroup(lonelyStudents []string, targetGroupSize int, newGroupSize int,
+	existingGroups [][]string, usedStudents map[string]bool, rules *groupRules, rng *rand.Rand) (bool, [][]string, map[string]bool) {
 
 	// Erstellt Kopien der Daten, um keine unerwünschten Seiteneffekte zu verursachen.
 	groupsCopy := make([][]string, len(existingGroups))
@@ -367,19 +1010,21 @@ func tryIntegrateIntoExistingGroup(lonelyStudent string, targetGroupSize int, ne
 	for i := range indices {
 		indices[i] = i
 	}
-	rand.Shuffle(len(indices), func(i, j int) {
+	rng.Shuffle(len(indices), func(i, j int) {
 		indices[i], indices[j] = indices[j], indices[i]
 	})
 
 	for _, idx := range indices { // Iteriert über die Gruppen.
 		group := groupsCopy[idx]
-		if len(group) == targetGroupSize { // Findet eine Gruppe der ursprünglichen Zielgröße (z.B. 2er-Gruppe bei 2er-Szenario).
-			potentialNewGroup := append([]string{}, group...) // Kopiert die Gruppe.
-			potentialNewGroup = append(potentialNewGroup, lonelyStudent) // Fügt den einsamen Schüler hinzu.
-
-			if isValidGroup(potentialNewGroup, constraints) { // Prüft, ob die neue, größere Gruppe gültig ist.
-				groupsCopy[idx] = potentialNewGroup        // Aktualisiert die Gruppe.
-				usedStudentsCopy[lonelyStudent] = true     // Markiert den Schüler als verwendet.
+		if len(group) == targetGroupSize && len(group)+len(lonelyStudents) == newGroupSize { // Findet eine Gruppe der passenden Größe.
+			potentialNewGroup := append([]string{}, group...)             // Kopiert die Gruppe.
+			potentialNewGroup = append(potentialNewGroup, lonelyStudents...) // Fügt die einsamen Schüler hinzu.
+
+			if isValidGroup(potentialNewGroup, rules) { // Prüft, ob die neue, größere Gruppe gültig ist.
+				groupsCopy[idx] = potentialNewGroup // Aktualisiert die Gruppe.
+				for _, s := range lonelyStudents {
+					usedStudentsCopy[s] = true // Markiert die Schüler als verwendet.
+				}
 				return true, groupsCopy, usedStudentsCopy // Erfolgreich integriert!
 			}
 		}
@@ -388,191 +1033,204 @@ func tryIntegrateIntoExistingGroup(lonelyStudent string, targetGroupSize int, ne
 }
 
 // ############################################################################################
-// formTwoPersonGroups versucht, primär 2er-Gruppen zu bilden.
-// Es hat spezielle Logik, um einen einzelnen Restschüler in eine 3er-Gruppe zu verwandeln.
-func formTwoPersonGroups(allStudents []string, constraints map[string][]string) ([][]string, []string) {
+// formGroups versucht, primär Gruppen der Zielgröße 'targetSize' zu bilden.
+// Restschüler werden für jede Gruppengröße gleich behandelt:
+// 1. Sind genug Restschüler übrig, wird daraus eine kleinere Restgruppe gebildet (mindestens 'minSize' Personen).
+// 2. Die übrigen Restschüler werden einzeln in bestehende Gruppen integriert (höchstens 'maxSize' Personen).
+// Pflichtpartner werden dabei immer gemeinsam eingeteilt.
+func formGroups(allStudents []string, targetSize, minSize, maxSize int, rules *groupRules, rng *rand.Rand) ([][]string, []string) {
+	// Unsinnige Grenzen werden auf die Zielgröße zurückgesetzt.
+	if minSize < 1 || minSize > targetSize {
+		minSize = targetSize
+	}
+	if maxSize < targetSize {
+		maxSize = targetSize
+	}
+
 	studentsToGroup := make([]string, len(allStudents))
 	copy(studentsToGroup, allStudents)
-	rand.Shuffle(len(studentsToGroup), func(i, j int) { // Mischt die Schülerliste.
-		studentsToGroup[i], studentsToGroup[j] = studentsToGroup[j], studentsToGroup[i] // Korrekter Tausch.
+	rng.Shuffle(len(studentsToGroup), func(i, j int) { // Mischt die Schülerliste.
+		studentsToGroup[i], studentsToGroup[j] = studentsToGroup[j], studentsToGroup[i]
 	})
 
-	var groups [][]string        // Die Liste der gebildeten Gruppen.
+	var groups [][]string                 // Die Liste der gebildeten Gruppen.
 	usedStudents := make(map[string]bool) // Map, um zu verfolgen, welche Schüler verwendet wurden.
 
-	// Versucht, so viele 2er-Gruppen wie möglich zu bilden.
-	groups, usedStudents = attemptToFormGroupsOfSize(2, studentsToGroup, usedStudents, groups, constraints)
+	// Versucht, so viele Gruppen der Zielgröße wie möglich zu bilden.
+	groups, usedStudents = attemptToFormGroupsOfSize(targetSize, studentsToGroup, usedStudents, groups, rules, rng)
+	currentlyUngrouped := collectUngrouped(studentsToGroup, usedStudents)
 
-	var currentlyUngrouped []string // Schüler, die nach der Hauptbildung übrig sind.
-	for _, student := range studentsToGroup {
-		if !usedStudents[student] {
-			currentlyUngrouped = append(currentlyUngrouped, student)
-		}
+	// Restgruppen: Aus den Restschülern werden möglichst große Gruppen zwischen Zielgröße und Mindestgröße gebildet.
+	for size := targetSize - 1; size >= minSize && len(currentlyUngrouped) >= size; size-- {
+		groups, usedStudents = attemptToFormGroupsOfSize(size, currentlyUngrouped, usedStudents, groups, rules, rng)
+		currentlyUngrouped = collectUngrouped(currentlyUngrouped, usedStudents)
 	}
 
-	// Spezialfall: Ein einzelner ungepaarter Schüler übrig.
-	if len(currentlyUngrouped) == 1 {
-		lonelyStudent := currentlyUngrouped[0]
-		// Versucht, den einzelnen Schüler in eine 2er-Gruppe zu integrieren, um eine 3er-Gruppe zu bilden.
-		integrated, updatedGroups, updatedUsedStudents := tryIntegrateIntoExistingGroup(lonelyStudent, 2, 3, groups, usedStudents, constraints)
-		if integrated {
-			groups = updatedGroups
-			usedStudents = updatedUsedStudents
-			currentlyUngrouped = []string{} // Der Schüler ist jetzt nicht mehr ungepaart.
+	// Einzelne Restschüler (bzw. Einheiten von Pflichtpartnern) werden in bestehende Gruppen integriert, kleinste Gruppen zuerst.
+	for _, lonelyStudents := range rules.splitIntoUnits(currentlyUngrouped) {
+		// Eine Einheit von Pflichtpartnern, die schon allein groß genug ist, bildet eine eigene Gruppe.
+		if len(lonelyStudents) >= minSize && len(lonelyStudents) <= maxSize && isValidGroup(lonelyStudents, rules) {
+			groups = append(groups, append([]string{}, lonelyStudents...))
+			markUsed(lonelyStudents, usedStudents)
+			continue
+		}
+		for size := minSize; size+len(lonelyStudents) <= maxSize; size++ {
+			integrated, updatedGroups, updatedUsedStudents := tryIntegrateIntoExistingGroup(lonelyStudents, size, size+len(lonelyStudents), groups, usedStudents, rules, rng)
+			if integrated {
+				groups = updatedGroups
+				usedStudents = updatedUsedStudents
+				break // Die Schüler sind jetzt in einer Gruppe.
+			}
 		}
 	}
 
-	var finalUngrouped []string // Endgültige Liste der ungepaarten Schüler.
-	for _, student := range allStudents {
+	return groups, collectUngrouped(allStudents, usedStudents) // Endgültige Liste der ungruppierten Schüler.
+}
+
+// ############################################################################################
+// collectUngrouped gibt alle Schüler aus 'students' zurück, die noch keiner Gruppe zugeteilt sind.
+// Die Reihenfolge von 'students' bleibt erhalten.
+func collectUngrouped(students []string, usedStudents map[string]bool) []string {
+	var ungrouped []string
+	for _, student := range students {
 		if !usedStudents[student] {
-			finalUngrouped = append(finalUngrouped, student)
+			ungrouped = append(ungrouped, student)
 		}
 	}
-	return groups, finalUngrouped
+	return ungrouped
 }
 
 // ############################################################################################
-// formThreePersonGroups versucht, primär 3er-Gruppen zu bilden.
-// Es hat spezielle Logik für 1 oder 2 Restschüler.
-func formThreePersonGroups(allStudents []string, constraints map[string][]string) ([][]string, []string) {
+// formGroupsByCount teilt die Schüler in genau 'groupCount' Gruppen ein.
+// Die Gruppengrößen unterscheiden sich dabei höchstens um eine Person.
+// Schüler, die wegen Konflikten keinen Platz finden, werden durch Tauschen untergebracht;
+// 		wenn auch das nicht gelingt, bleiben sie ungruppiert.
+// Pflichtpartner werden als Einheit platziert und getauscht.
+func formGroupsByCount(allStudents []string, groupCount int, rules *groupRules, rng *rand.Rand) ([][]string, []string) {
+	if groupCount < 1 || len(allStudents) == 0 { // Ohne Gruppen kann niemand eingeteilt werden.
+		return nil, collectUngrouped(allStudents, map[string]bool{})
+	}
+	if groupCount > len(allStudents) { // Mehr Gruppen als Schüler ergeben keinen Sinn.
+		groupCount = len(allStudents)
+	}
+
 	studentsToGroup := make([]string, len(allStudents))
 	copy(studentsToGroup, allStudents)
-	rand.Shuffle(len(studentsToGroup), func(i, j int) { // Mischt die Schülerliste.
-		studentsToGroup[i], studentsToGroup[j] = studentsToGroup[j], studentsToGroup[i] // Korrekter Tausch.
+	rng.Shuffle(len(studentsToGroup), func(i, j int) { // Mischt die Schülerliste.
+		studentsToGroup[i], studentsToGroup[j] = studentsToGroup[j], studentsToGroup[i]
 	})
 
-	var groups [][]string
+	capacities := groupSizesForCount(len(studentsToGroup), groupCount) // Plätze pro Gruppe.
+	groups := make([][]string, groupCount)
 	usedStudents := make(map[string]bool)
 
-	// Versucht, so viele 3er-Gruppen wie möglich zu bilden.
-	groups, usedStudents = attemptToFormGroupsOfSize(3, studentsToGroup, usedStudents, groups, constraints)
-
-	var currentlyUngrouped []string
-	for _, student := range studentsToGroup {
-		if !usedStudents[student] {
-			currentlyUngrouped = append(currentlyUngrouped, student)
+	// Erster Durchgang: Jeder Schüler (mit seinen Pflichtpartnern) kommt in eine zufällige Gruppe
+	// 		mit freiem Platz und ohne Konflikt.
+	var leftovers [][]string
+	for _, unit := range rules.splitIntoUnits(studentsToGroup) {
+		if placeUnitInFreeGroup(unit, -1, groups, capacities, rules, rng) {
+			markUsed(unit, usedStudents)
+		} else {
+			leftovers = append(leftovers, unit)
 		}
 	}
 
-	// Spezialfall: Ein einzelner ungepaarter Schüler.
-	if len(currentlyUngrouped) == 1 {
-		lonelyStudent := currentlyUngrouped[0]
-		// Versucht, ihn in eine 3er-Gruppe zu integrieren, um eine 4er-Gruppe zu bilden.
-		integrated, updatedGroups, updatedUsedStudents := tryIntegrateIntoExistingGroup(lonelyStudent, 3, 4, groups, usedStudents, constraints)
-		if integrated {
-			groups = updatedGroups
-			usedStudents = updatedUsedStudents
-			currentlyUngrouped = []string{}
-		}
-	} else if len(currentlyUngrouped) == 2 { // Spezialfall: Zwei ungepaarte Schüler.
-		potentialGroup := currentlyUngrouped // Bilden eine 2er-Gruppe aus den Restschülern.
-		if isValidGroup(potentialGroup, constraints) {
-			groups = append(groups, potentialGroup) // Fügt die 2er-Restgruppe hinzu.
-			for _, s := range potentialGroup {
-				usedStudents[s] = true
-			}
-			currentlyUngrouped = []string{}
+	// Zweiter Durchgang: Für die Restschüler wird durch Tauschen ein Platz geschaffen.
+	for _, unit := range leftovers {
+		if swapIntoGroups(unit, groups, capacities, rules, rng) {
+			markUsed(unit, usedStudents)
 		}
 	}
 
-	var finalUngrouped []string
-	for _, student := range allStudents {
-		if !usedStudents[student] {
-			finalUngrouped = append(finalUngrouped, student)
+	// Leere Gruppen (nur möglich, wenn niemand hineinpasste) werden nicht zurückgegeben.
+	var finalGroups [][]string
+	for _, group := range groups {
+		if len(group) > 0 {
+			finalGroups = append(finalGroups, group)
 		}
 	}
-	return groups, finalUngrouped
+	return finalGroups, collectUngrouped(allStudents, usedStudents)
 }
 
 // ############################################################################################
-// formFourPersonGroups versucht, primär 4er-Gruppen zu bilden.
-// Es hat spezielle Logik für 1, 2 oder 3 Restschüler.
-func formFourPersonGroups(allStudents []string, constraints map[string][]string) ([][]string, []string) {
-	studentsToGroup := make([]string, len(allStudents))
-	copy(studentsToGroup, allStudents)
-	rand.Shuffle(len(studentsToGroup), func(i, j int) { // Mischt die Schülerliste.
-		studentsToGroup[i], studentsToGroup[j] = studentsToGroup[j], studentsToGroup[i] // Korrekter Tausch.
-	})
+// groupSizesForCount verteilt 'studentCount' Schüler möglichst gleichmäßig auf 'groupCount' Gruppen.
+// Beispiel: 23 Schüler in 5 Gruppen ergibt [5 5 5 4 4].
+func groupSizesForCount(studentCount int, groupCount int) []int {
+	sizes := make([]int, groupCount)
+	for i := range sizes {
+		sizes[i] = studentCount / groupCount
+		if i < studentCount%groupCount { // Die ersten Gruppen bekommen je eine Person mehr.
+			sizes[i]++
+		}
+	}
+	return sizes
+}
 
-	var groups [][]string
-	usedStudents := make(map[string]bool)
+// ############################################################################################
+// placeUnitInFreeGroup fügt einen Schüler samt Pflichtpartnern ('unit') in eine zufällig gewählte Gruppe ein,
+// 		die noch genug freie Plätze hat und in der kein Konflikt entsteht.
+// Die Gruppe mit dem Index 'skipIndex' wird dabei ausgelassen (-1 für keine).
+func placeUnitInFreeGroup(unit []string, skipIndex int, groups [][]string, capacities []int, rules *groupRules, rng *rand.Rand) bool {
+	for _, idx := range rng.Perm(len(groups)) { // Zufällige Reihenfolge der Gruppen.
+		if idx == skipIndex || len(groups[idx])+len(unit) > capacities[idx] {
+			continue // Gruppe ausgelassen oder zu voll.
+		}
+		potentialGroup := append(append([]string{}, groups[idx]...), unit...)
+		if isValidGroup(potentialGroup, rules) {
+			groups[idx] = potentialGroup
+			return true
+		}
+	}
+	return false
+}
 
-	// Versucht, so viele 4er-Gruppen wie möglich zu bilden.
-	groups, usedStudents = attemptToFormGroupsOfSize(4, studentsToGroup, usedStudents, groups, constraints)
+// ############################################################################################
+// swapIntoGroups versucht, einen Restschüler (samt Pflichtpartnern) unterzubringen, indem er
+// 		einen anderen Schüler (samt dessen Pflichtpartnern) aus seiner Gruppe verdrängt.
+// Der verdrängte Schüler muss dann in eine andere Gruppe mit freiem Platz passen.
+// Die Gruppengrößen bleiben dadurch ausgeglichen.
+func swapIntoGroups(unit []string, groups [][]string, capacities []int, rules *groupRules, rng *rand.Rand) bool {
+	for _, idx := range rng.Perm(len(groups)) {
+		for _, member := range groups[idx] {
+			displaced := rules.unitOf(member) // Wird gemeinsam verdrängt.
+			if len(groups[idx])-len(displaced)+len(unit) > capacities[idx] {
+				continue // Nicht genug Platz nach dem Tausch.
+			}
 
-	var currentlyUngrouped []string
-	for _, student := range studentsToGroup {
-		if !usedStudents[student] {
-			currentlyUngrouped = append(currentlyUngrouped, student)
-		}
-	}
-
-	// Spezialfall: Ein einzelner ungepaarter Schüler.
-	if len(currentlyUngrouped) == 1 {
-		lonelyStudent := currentlyUngrouped[0]
-		// Versucht, ihn in eine 4er-Gruppe zu integrieren, um eine 5er-Gruppe zu bilden.
-		integrated, updatedGroups, updatedUsedStudents := tryIntegrateIntoExistingGroup(lonelyStudent, 4, 5, groups, usedStudents, constraints)
-		if integrated {
-			groups = updatedGroups
-			usedStudents = updatedUsedStudents
-			currentlyUngrouped = []string{}
-		}
-	} else if len(currentlyUngrouped) == 2 { // Spezialfall: Zwei ungepaarte Schüler.
-		// Versucht, beide einzeln in bestehende 4er-Gruppen zu integrieren, um 5er-Gruppen zu bilden.
-		tempGroups := make([][]string, len(groups))
-		copy(tempGroups, groups)
-		tempUsedStudents := make(map[string]bool)
-		for k, v := range usedStudents {
-			tempUsedStudents[k] = v
-		}
-
-		integratedCount := 0
-		var remainingUngrouped []string // Schüler, die auch nach diesen Versuchen ungepaart bleiben.
-
-		lonelyStudent1 := currentlyUngrouped[0]
-		integrated1, updatedGroups1, updatedUsedStudents1 := tryIntegrateIntoExistingGroup(lonelyStudent1, 4, 5, tempGroups, tempUsedStudents, constraints)
-
-		if integrated1 {
-			groups = updatedGroups1
-			usedStudents = updatedUsedStudents1
-			integratedCount++
-
-			if len(currentlyUngrouped) > 1 {
-				lonelyStudent2 := currentlyUngrouped[1]
-				integrated2, updatedGroups2, updatedUsedStudents2 := tryIntegrateIntoExistingGroup(lonelyStudent2, 4, 5, groups, usedStudents, constraints)
-				if integrated2 {
-					groups = updatedGroups2
-					usedStudents = updatedUsedStudents2
-					integratedCount++
-				} else {
-					remainingUngrouped = append(remainingUngrouped, lonelyStudent2)
+			// Die Gruppe ohne den verdrängten Schüler, dafür mit dem Restschüler.
+			isDisplaced := make(map[string]bool)
+			for _, s := range displaced {
+				isDisplaced[s] = true
+			}
+			var potentialGroup []string
+			for _, s := range groups[idx] {
+				if !isDisplaced[s] {
+					potentialGroup = append(potentialGroup, s)
 				}
 			}
-		} else {
-			// Wenn der erste Schüler nicht integriert werden konnte, bleiben beide ungepaart.
-			remainingUngrouped = append(remainingUngrouped, currentlyUngrouped...)
-		}
-
-		currentlyUngrouped = remainingUngrouped
+			potentialGroup = append(potentialGroup, unit...)
+			if !isValidGroup(potentialGroup, rules) {
+				continue
+			}
 
-	} else if len(currentlyUngrouped) == 3 { // Spezialfall: Drei ungepaarte Schüler.
-		potentialGroup := currentlyUngrouped // Bilden eine 3er-Gruppe aus den Restschülern.
-		if isValidGroup(potentialGroup, constraints) {
-			groups = append(groups, potentialGroup) // Fügt die 3er-Restgruppe hinzu.
-			for _, s := range potentialGroup {
-				usedStudents[s] = true
+			// Der verdrängte Schüler braucht einen neuen Platz in einer anderen Gruppe.
+			original := groups[idx]
+			groups[idx] = potentialGroup
+			if placeUnitInFreeGroup(displaced, idx, groups, capacities, rules, rng) {
+				return true // Tausch erfolgreich.
 			}
-			currentlyUngrouped = []string{}
+			groups[idx] = original // Tausch rückgängig machen.
 		}
 	}
+	return false
+}
 
-	var finalUngrouped []string
-	for _, student := range allStudents {
-		if !usedStudents[student] {
-			finalUngrouped = append(finalUngrouped, student)
-		}
+// ############################################################################################
+// markUsed markiert alle Schüler einer Einheit als eingeteilt.
+func markUsed(students []string, usedStudents map[string]bool) {
+	for _, student := range students {
+		usedStudents[student] = true
 	}
-	return groups, finalUngrouped
 }
 
 // ############################################################################################
@@ -624,162 +1282,516 @@ func checkSymmetricConstraints(constraints map[string][]string) error {
 }
 
 
+// ############################################################################################
+// checkTogetherConstraints prüft die Pflichtpartner (Abschnitt [zusammen]) wie checkSymmetricConstraints:
+// 1. Symmetrie: Wenn A mit B zusammen muss, sollte auch B mit A zusammen müssen.
+// 2. Widersprüche: Schüler, die (auch über andere Pflichtpartner) zusammen müssen,
+// 		dürfen keinen Konflikt miteinander haben. Sonst gibt es keine gültige Gruppe für sie.
+func checkTogetherConstraints(together map[string][]string, constraints map[string][]string) error {
+	var issues []string // Sammelt alle gefundenen Probleme.
+
+	for _, studentA := range sortedKeys(together) { // Sortiert, damit die Meldungen stabil sind.
+		sortedPartners := append([]string{}, together[studentA]...)
+		sort.Strings(sortedPartners)
+		for _, studentB := range sortedPartners {
+			foundAInB := false
+			for _, s := range together[studentB] { // Prüft, ob studentA bei studentB als Pflichtpartner steht.
+				if s == studentA {
+					foundAInB = true
+					break
+				}
+			}
+			if !foundAInB {
+				issues = append(issues,
+					fmt.Sprintf("Asymmetrie gefunden: '%s' muss mit '%s' zusammen, aber bei '%s' fehlt '%s'.",
+						studentA, studentB, studentB, studentA))
+			}
+		}
+	}
+
+	// Alle erwähnten Namen bilden die "Klasse" für die Einheiten-Berechnung.
+	var names []string
+	seen := make(map[string]bool)
+	for _, student := range sortedKeys(together) {
+		for _, name := range append([]string{student}, together[student]...) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	rules := newGroupRules(&Config{Schuelerliste: names, Constraints: constraints, Together: together})
+	for _, unit := range rules.splitIntoUnits(names) {
+		for i, studentA := range unit {
+			for _, studentB := range unit[i+1:] {
+				if rules.conflict(studentA, studentB) {
+					issues = append(issues,
+						fmt.Sprintf("Widerspruch gefunden: '%s' und '%s' müssen zusammen (Gruppe: %s), dürfen aber laut Konflikten nicht zusammen.",
+							studentA, studentB, strings.Join(unit, ", ")))
+				}
+			}
+		}
+	}
+
+	if len(issues) > 0 { // Wenn Probleme gefunden wurden, gib einen Fehler zurück.
+		return fmt.Errorf("Inkonsistenzen bei den Pflichtpartnern gefunden:\n%s",
+			strings.Join(issues, "\n"))
+	}
+	return nil
+}
+
+// ############################################################################################
+// issueLines zerlegt die Fehlermeldung einer Prüfung (z.B. checkSymmetricConstraints)
+// 		in die einzelnen gefundenen Probleme, ohne die einleitende Zeile.
+func issueLines(err error) []string {
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) > 1 {
+		return lines[1:]
+	}
+	return lines
+}
+
+// ############################################################################################
+// groupScenario beschreibt ein Gruppierungs-Szenario.
+// Neben der angestrebten Gruppengröße legt es fest, wie klein Restgruppen
+// 		und wie groß Gruppen mit integrierten Restschülern werden dürfen.
+// Ist 'GroupCount' gesetzt, wird stattdessen eine feste Anzahl Gruppen gebildet.
+type groupScenario struct {
+	TargetSize int // Angestrebte Gruppengröße.
+	MinSize    int // Kleinste erlaubte Gruppengröße (für Restgruppen).
+	MaxSize    int // Größte erlaubte Gruppengröße (wenn Restschüler integriert werden).
+	GroupCount int // Anzahl Gruppen im "Anzahl Gruppen"-Modus (0 = Gruppengröße-Modus).
+}
+
+// scenarioForSize erstellt ein Szenario für eine beliebige Gruppengröße.
+// Restgruppen dürfen eine Person kleiner (mindestens 2), Gruppen mit Restschülern eine Person größer sein,
+// 		wie bei den festen 2er-, 3er- und 4er-Szenarien.
+func scenarioForSize(size int) groupScenario {
+	minSize := size - 1
+	if minSize < 2 {
+		minSize = size // Kleiner als 2 wird eine Restgruppe nie.
+	}
+	return groupScenario{TargetSize: size, MinSize: minSize, MaxSize: size + 1}
+}
+
+// form bildet einmal Gruppen nach diesem Szenario.
+func (s groupScenario) form(allStudents []string, rules *groupRules, rng *rand.Rand) ([][]string, []string) {
+	if s.GroupCount > 0 {
+		return formGroupsByCount(allStudents, s.GroupCount, rules, rng)
+	}
+	return formGroups(allStudents, s.TargetSize, s.MinSize, s.MaxSize, rules, rng)
+}
+
+// title gibt die Bezeichnung des Szenarios für die Ausgabe zurück (z.B. "3er-Gruppen" oder "7 Gruppen").
+func (s groupScenario) title() string {
+	if s.GroupCount > 0 {
+		return fmt.Sprintf("%d Gruppen", s.GroupCount)
+	}
+	return fmt.Sprintf("%der-Gruppen", s.TargetSize)
+}
+
+// deviation gibt an, wie weit die Gruppengrößen einer Einteilung von der Zielgröße abweichen (siehe sizeDeviation).
+// Im "Anzahl Gruppen"-Modus sind die Größen fest vorgegeben, dann ist die Abweichung immer 0.
+func (s groupScenario) deviation(groups [][]string) int {
+	if s.GroupCount > 0 {
+		return 0
+	}
+	sizes := make([]int, len(groups))
+	for i, group := range groups {
+		sizes[i] = len(group)
+	}
+	return sizeDeviation(sizes, s.TargetSize)
+}
+
+// minimalDeviation gibt die kleinste mögliche Abweichung für 'studentCount' Schüler zurück
+// 		(0, wenn keine vollständige Einteilung mit den erlaubten Größen möglich ist).
+func (s groupScenario) minimalDeviation(studentCount int) int {
+	if s.GroupCount > 0 {
+		return 0
+	}
+	if deviation := minimalSizeDeviation(studentCount, s.TargetSize, s.MinSize, s.MaxSize); deviation > 0 {
+		return deviation
+	}
+	return 0
+}
+
+// ############################################################################################
+// sizeDeviation gibt die Summe der Abweichungen der Gruppengrößen von der Zielgröße zurück
+// 		(z.B. [4 3 2] bei Zielgröße 3 ergibt 2). Je kleiner, desto näher an der gewünschten Einteilung.
+func sizeDeviation(sizes []int, targetSize int) int {
+	total := 0
+	for _, size := range sizes {
+		if size > targetSize {
+			total += size - targetSize
+		} else {
+			total += targetSize - size
+		}
+	}
+	return total
+}
+
+// minimalSizeDeviation gibt die kleinste mögliche Abweichung von der Zielgröße zurück,
+// 		mit der 'studentCount' Schüler vollständig in Gruppen zwischen 'minSize' und 'maxSize' passen.
+// Gibt -1 zurück, wenn es keine solche Einteilung gibt.
+func minimalSizeDeviation(studentCount, targetSize, minSize, maxSize int) int {
+	if minSize < 1 || minSize > targetSize { // Gleiche Korrektur wie in formGroups.
+		minSize = targetSize
+	}
+	if maxSize < targetSize {
+		maxSize = targetSize
+	}
+	best := make([]int, studentCount+1) // best[n] = kleinste Abweichung für n Schüler (-1 = unmöglich).
+	for n := 1; n <= studentCount; n++ {
+		best[n] = -1
+		for size := minSize; size <= maxSize && size <= n; size++ {
+			if best[n-size] < 0 {
+				continue
+			}
+			if deviation := best[n-size] + sizeDeviation([]int{size}, targetSize); best[n] < 0 || deviation < best[n] {
+				best[n] = deviation
+			}
+		}
+	}
+	return best[studentCount]
+}
+
+// ############################################################################################
+// scenarioResult ist die beste gefundene Einteilung eines Szenarios.
+type scenarioResult struct {
+	Scenario   groupScenario // Das berechnete Szenario.
+	Groups     [][]string    // Die gebildeten Gruppen.
+	Ungrouped  []string      // Schüler, die keiner Gruppe zugeteilt werden konnten.
+	Infeasible bool          // true, wenn die exakte Suche bewiesen hat, dass keine vollständige Einteilung existiert.
+	Seed       int64         // Startwert der Zufallsquelle, mit dem das Ergebnis wiederholt werden kann.
+	Round      int           // Nummer der Runde in einem Rotationsplan (0 = einzelne Einteilung).
+	Attempts   int           // Anzahl durchgeführter Versuche der Zufallssuche.
+	Score      int           // Bewertung der Einteilung (siehe scoreGrouping).
+	Hints      []string      // Hinweise der Analyse (siehe diagnoseScenario).
+}
+
+// label gibt die Bezeichnung des Ergebnisses für Ausgaben und Exporte zurück
+// 		(z.B. "3er-Gruppen" oder im Rotationsplan "Runde 2 (4 Gruppen)").
+func (r scenarioResult) label() string {
+	if r.Round > 0 {
+		return fmt.Sprintf("Runde %d (%s)", r.Round, r.Scenario.title())
+	}
+	return r.Scenario.title()
+}
+
+// ############################################################################################
+// runScenario wiederholt die Gruppenbildung für ein Szenario mehrmals
+// 		und gibt das beste gefundene Ergebnis auf der Konsole aus.
+// Bleiben Schüler ungruppiert, prüft die exakte Suche (solveExact), ob eine vollständige
+// 		Einteilung überhaupt möglich ist. Mit 'exactFirst' wird die exakte Suche zuerst verwendet.
+// Alle Zufallsentscheidungen kommen aus einer Zufallsquelle mit dem Startwert 'seed',
+// 		damit dieselbe Einteilung mit '-seed' wiederholt werden kann.
+func runScenario(config *Config, rules *groupRules, scenario groupScenario, search searchSettings, exactFirst bool, seed int64) scenarioResult {
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", 62))
+	if scenario.GroupCount > 0 {
+		fmt.Printf("=== Einteilung in %s (Größen unterscheiden sich höchstens um 1).\n", scenario.title())
+	} else {
+		fmt.Printf("=== Einteilung in %s (eventuell mit Anpassung).\n", scenario.title())
+	}
+
+	rng := rand.New(rand.NewSource(seed)) // Jedes Szenario beginnt mit demselben Startwert.
+	var bestGroups [][]string              // Speichert die besten gefundenen Gruppen.
+	var bestUngrouped []string             // Speichert die ungruppierten Schüler für das beste Ergebnis.
+	exactDone := false                     // Wurde die exakte Suche bereits durchgeführt?
+	status := exactAborted     // Ergebnis der exakten Suche (nur gültig, wenn 'exactDone').
+	budget := &exactBudget{}   // Suchschritte für alle exakten Suchen dieses Szenarios.
+
+	if exactFirst { // Exakte Suche zuerst: liefert eine vollständige Einteilung, falls es eine gibt.
+		bestGroups, status = solveExact(config.Schuelerliste, scenario, rules, rng, budget)
+		exactDone = true
+	}
+	attempts := 0                           // Anzahl der Versuche der Zufallssuche.
+	if !exactDone || status != exactFound { // Zufallssuche (auch als Teillösung, wenn die exakte Suche scheitert).
+		bestGroups, bestUngrouped, attempts = findBestRandomGrouping(config, rules, scenario, search, rng)
+	}
+	if !exactDone && len(bestUngrouped) > 0 { // Zufallssuche unvollständig: Ist eine vollständige Einteilung möglich?
+		var exactGroups [][]string
+		exactGroups, status = solveExact(config.Schuelerliste, scenario, rules, rng, budget)
+		exactDone = true
+		if status == exactFound {
+			fmt.Println("ℹ️ Die Zufallssuche blieb unvollständig, die exakte Suche hat eine vollständige Einteilung gefunden.")
+			bestGroups, bestUngrouped = exactGroups, nil
+		}
+	}
+
+	// Ausgabe der Ergebnisse.
+	if len(bestGroups) == 0 && len(bestUngrouped) > 0 {
+		fmt.Printf("❌ Es konnten keine gültigen %s gebildet werden. Alle Schüler sind ungruppiert.\n", scenario.title())
+	} else if len(bestGroups) == 0 {
+		fmt.Println("❌ Es konnten keine Gruppen gebildet werden.")
+	} else {
+		for i, group := range bestGroups {
+			fmt.Printf("Gruppe %d (%d Personen): %v\n", i+1, len(group), group)
+		}
+	}
+	if rules.hasSoftConstraints() && len(bestGroups) > 0 {
+		fmt.Printf("⭐ %s\n", describeScore(bestGroups, rules))
+	}
+	if len(bestUngrouped) > 0 {
+		fmt.Printf("❗️ Ungruppierte Schüler: %v\n", bestUngrouped)
+		if exactDone && status == exactInfeasible {
+			fmt.Printf("❗️ Die exakte Suche hat bewiesen: Eine vollständige Einteilung in %s ist mit diesen Konflikten unmöglich.\n", scenario.title())
+		} else if exactDone && status == exactAborted {
+			fmt.Printf("❗️ Die exakte Suche wurde nach %d Schritten abgebrochen. Ob eine vollständige Einteilung möglich ist, bleibt offen.\n", exactSearchLimit)
+		}
+	} else {
+		fmt.Printf("✅ Alle Schüler wurden erfolgreich in %s eingeteilt!\n", scenario.title())
+	}
+	fmt.Printf("ℹ️ %s\n", describeSeed(seed, search))
+	infeasible := len(bestUngrouped) > 0 && exactDone && status == exactInfeasible
+	return scenarioResult{Scenario: scenario, Groups: bestGroups, Ungrouped: bestUngrouped, Infeasible: infeasible, Seed: seed,
+		Attempts: attempts, Score: scoreGrouping(bestGroups, rules)}
+}
+
 // ############################################################################################
 // main ist der Haupteinstiegspunkt des Programms.
 func main() {
+	// Befehl und Optionen lesen (z.B. 'klassenmischer mischen -groesse 3').
+	// Ohne Argumente (z.B. bei Doppelklick) werden wie bisher alle Szenarien berechnet.
+	options, err := parseCommandLine(os.Args[1:])
+	if err == flag.ErrHelp { // '-h' oder '-help': Die Hilfe wurde bereits ausgegeben.
+		return
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(exitUsage)
+	}
+	// Wird der Export nach stdout geschrieben, erscheinen alle Meldungen stattdessen auf stderr.
+	exportOutput := redirectConsoleForExport(options)
+
+	// Bei mehreren Klassen (Ordner 'klassen'): Klasse mit '-klasse' oder über ein Menü wählen.
+	if err := selectClass(options); err != nil {
+		fmt.Println(err)
+		os.Exit(exitUsage)
+	}
+	if options.Command == "erstellen" { // Braucht keine bestehende Konfiguration.
+		if err := runInitCommand(options); err != nil {
+			fmt.Println(err)
+			os.Exit(exitError)
+		}
+		return
+	}
 
 	fmt.Println()
 	fmt.Println(strings.Repeat("=", 62))
 	fmt.Println("=== Macht zufällige Gruppen für deine Klasse.")
+	if options.ClassName != "" { // Bei mehreren Klassen: Welche wird gemischt?
+		fmt.Printf("=== Klasse: %s\n", options.ClassName)
+	}
 	fmt.Println()
 
-	configPath := "klasse.toml" // Der Standardname der Konfigurationsdatei.
-	config, err := readTomlConfig(configPath) // Versucht, die Konfiguration zu lesen oder zu erstellen.
+	config, err := readTomlConfig(options.ConfigPath) // Versucht, die Konfiguration zu lesen oder zu erstellen.
+
+	if _, created := err.(*ConfigFileCreatedError); created && options.Command == "bearbeiten" { // Die neue Musterdatei gleich bearbeiten.
+		fmt.Println("\nℹ️ Die Musterdatei kann jetzt direkt bearbeitet werden.")
+		config, err = readTomlConfig(options.ConfigPath)
+	}
 
 	if err != nil { // Wenn ein Fehler auftritt (z.B. Datei nicht gefunden und neu erstellt).
 		if _, ok := err.(*ConfigFileCreatedError); ok { // Prüft, ob es unser spezieller "Datei erstellt"-Fehler ist.
 			fmt.Println(err.Error()) // Gibt die Nachricht aus, dass eine neue Datei erstellt wurde.
 			// Warte auf Benutzereingabe, bevor das Programm beendet wird, wenn eine Datei erstellt wurde.
+			if options.Batch { // Nur für Skripte ein eigener Exit-Code, bei Doppelklick wie bisher 0.
+				os.Exit(exitConfigCreated)
+			}
 			fmt.Println("\n❗️ Drücke Enter, um das Programm zu beenden und die Konfigurationsdatei zu überprüfen.")
 			fmt.Scanln() // Wartet auf Enter-Taste.
-			os.Exit(0)   // Beendet das Programm sauber.
+			os.Exit(exitSuccess) // Beendet das Programm sauber.
 		} else { // Wenn es ein anderer, schwerwiegender Fehler beim Laden der Konfiguration ist.
-			log.Fatalf("❌ Fehler beim Laden der Konfiguration: %v", err) // Gibt den Fehler aus und beendet das Programm abrupt.
+			log.Printf("❌ Fehler beim Laden der Konfiguration: %v", err) // Gibt den Fehler aus und beendet das Programm abrupt.
+			os.Exit(exitConfigInvalid)
+		}
+	}
+
+	// Voreinstellungen aus [einstellungen] gelten für alle Optionen, die beim Aufruf fehlen.
+	if applied := applySettings(options, config.Settings); len(applied) > 0 {
+		fmt.Printf("ℹ️ Einstellungen aus [%s]: %s\n", settingsSection, strings.Join(applied, ", "))
+		if os.Stdout == exportOutput { // Auch '-format' kann aus der Datei kommen.
+			exportOutput = redirectConsoleForExport(options)
 		}
 	}
 
+	if options.Command == "verlauf" { // Nur die gespeicherten Einteilungen anzeigen.
+		if err := runHistoryCommand(config); err != nil {
+			fmt.Println(err)
+			os.Exit(exitError)
+		}
+		return
+	}
+	if options.Command == "bearbeiten" { // Schülerliste und Konflikte in Menüs bearbeiten.
+		if err := runEditCommand(config); err != nil {
+			fmt.Println(err)
+			os.Exit(exitError)
+		}
+		return
+	}
+	if options.Command == "symmetrisch" { // Fehlende Gegenrichtungen der Konflikte in der Datei ergänzen.
+		if _, err := runSymmetrizeCommand(config); err != nil {
+			fmt.Println(err)
+			os.Exit(exitError)
+		}
+		return
+	}
+
 	// Zeigt die geladenen Schüler und Constraints an.
 	// fmt.Println("Schülerliste:", config.Schuelerliste)
 	// fmt.Println("Constraints:", config.Constraints)
 
+	// Die Szenarien, die nacheinander berechnet werden.
+	// Die Grenzen entsprechen den bisherigen Anpassungen (z.B. 3er-Gruppen im 2er-Szenario).
+	scenarios := []groupScenario{
+		{TargetSize: 2, MinSize: 2, MaxSize: 3},
+		{TargetSize: 3, MinSize: 2, MaxSize: 4},
+		{TargetSize: 4, MinSize: 3, MaxSize: 5},
+	}
+	if options.GroupSize > 0 { // Eigene Gruppengröße: nur dieses eine Szenario berechnen.
+		scenarios = []groupScenario{scenarioForSize(options.GroupSize)}
+	}
+	if options.GroupCount > 0 { // "Anzahl Gruppen"-Modus: nur dieses eine Szenario berechnen.
+		scenarios = []groupScenario{{GroupCount: options.GroupCount}}
+	}
+
+	var warnings []string // Alle Warnungen der Prüfungen (für den Export, z.B. '-format json').
+
+	fmt.Println("\n=== Prüfe die Konfigurationsdatei.")
+	checkStudentNames(config) // Findet Tippfehler wie "Jonas" statt "Jonas M." und doppelte Namen.
+	config.Report.print()     // Alle Probleme beim Lesen in einem Stück, mit Zeile und Spalte.
+	if len(config.Report.Issues) > 0 {
+		warnings = append(warnings, config.Report.lines()...)
+	}
+	config.Schuelerliste = uniqueNames(config.Schuelerliste) // Doppelte Namen nur einmal einteilen.
+
 	fmt.Println("\n=== Prüfe unverträgliche Paare auf Symmetrie.")
 	err = checkSymmetricConstraints(config.Constraints) // Prüft die Symmetrie der Constraints.
 	if err != nil {
+		warnings = append(warnings, issueLines(err)...)
 		fmt.Printf("❗️ Warnung: Unsymmetrische Paare in klasse.toml gefunden: %v\n", err)
 		fmt.Println("Die Gruppierung wird fortgesetzt, aber es wird empfohlen, die Konflikte zu korrigieren.")
 	} else {
 		fmt.Println("✅ Alle Paare sind symmetrisch. Weiter mit der Gruppierung.")
 	}
 
-	const attempts = 1000 // Anzahl der Versuche, Gruppen zu bilden (wegen Zufälligkeit).
-
-	// --- Szenario 1: Einteilung in 2er-Gruppen ---
-	fmt.Println()
-	fmt.Println(strings.Repeat("=", 62))
-	fmt.Println("=== Einteilung in 2er-Gruppen (eventuell mit Anpassung).")
-	bestGroups2er := [][]string{}       // Speichert die besten gefundenen 2er-Gruppen.
-	bestUngrouped2er := []string{}      // Speichert die ungepaarten Schüler für das beste Ergebnis.
-	maxGroupedStudents2er := -1         // Verfolgt die maximale Anzahl erfolgreich gruppierter Schüler.
+	fmt.Println("\n=== Prüfe Pflichtpartner auf Symmetrie und Widersprüche.")
+	err = checkTogetherConstraints(config.Together, config.Constraints) // Prüft den Abschnitt [zusammen].
+	if err != nil {
+		warnings = append(warnings, issueLines(err)...)
+		fmt.Printf("❗️ Warnung: Probleme mit den Pflichtpartnern in klasse.toml gefunden: %v\n", err)
+		fmt.Println("Die Gruppierung wird fortgesetzt, aber es wird empfohlen, den Abschnitt [zusammen] zu korrigieren.")
+	} else {
+		fmt.Println("✅ Alle Pflichtpartner sind symmetrisch und widerspruchsfrei.")
+	}
 
-	for i := 0; i < attempts; i++ { // Wiederholt den Gruppierungsprozess mehrmals.
-		currentGroups, currentUngrouped := formTwoPersonGroups(config.Schuelerliste, config.Constraints)
-		currentGroupedStudents := len(config.Schuelerliste) - len(currentUngrouped) // Anzahl der gruppierten Schüler in diesem Versuch.
+	rules := newGroupRules(config) // Regeln für alle Szenarien.
 
-		if currentGroupedStudents > maxGroupedStudents2er { // Wenn dieser Versuch besser war.
-			maxGroupedStudents2er = currentGroupedStudents
-			bestGroups2er = currentGroups
-			bestUngrouped2er = currentUngrouped
-			if len(currentUngrouped) == 0 { // Wenn alle Schüler gruppiert wurden, ist dies das beste Ergebnis, Abbruch.
-				break
-			}
-		}
+	// Der Verlauf früherer Einteilungen: Wiederholte Paare werden möglichst vermieden.
+	historyPath := historyPathFor(config.Path)
+	history, err := loadHistory(historyPath)
+	if err != nil {
+		fmt.Printf("❗️ Warnung: %v\nDie Gruppierung wird ohne Verlauf fortgesetzt.\n", err)
+		history = &groupingHistory{}
+	} else if len(history.Sessions) > 0 {
+		fmt.Printf("\nℹ️ Verlauf geladen: %d gespeicherte Einteilungen, die letzten %d werden berücksichtigt.\n",
+			len(history.Sessions), recentHistorySessions)
 	}
-	// Ausgabe der Ergebnisse für Szenario 1.
-	if len(bestGroups2er) == 0 && len(bestUngrouped2er) > 0 {
-		fmt.Println("❌ Es konnten keine gültigen 2er- oder 3er-Gruppen gebildet werden. Alle Schüler sind ungepaart.")
-	} else if len(bestGroups2er) == 0 {
-		fmt.Println("❌ Es konnten keine 2er- oder 3er-Gruppen gebildet werden.")
-	} else {
-		for i, group := range bestGroups2er {
-			fmt.Printf("Gruppe %d (%d Personen): %v\n", i+1, len(group), group)
+	rules.repeatCounts = history.recentPairCounts(recentHistorySessions)
+
+	fmt.Println("\n=== Prüfe, ob die Konflikte eine vollständige Einteilung zulassen.")
+	hintsFound := false
+	hints := make([][]string, len(scenarios)) // Hinweise je Szenario (für den Export).
+	for i, scenario := range scenarios {      // Die Analyse hängt von der Gruppengröße ab.
+		hints[i] = diagnoseScenario(config.Schuelerliste, scenario, rules)
+		for _, hint := range hints[i] {
+			fmt.Printf("❗️ %s: %s\n", scenario.title(), hint)
+			hintsFound = true
 		}
 	}
-	if len(bestUngrouped2er) > 0 {
-		fmt.Printf("❗️ Ungruppierte Schüler: %v\n", bestUngrouped2er)
-	} else {
-		fmt.Println("✅ Alle Schüler wurden erfolgreich in 2er-Gruppen eingeteilt!")
+	if !hintsFound {
+		fmt.Println("✅ Keine überbeschränkten Schüler oder Konfliktgruppen gefunden.")
 	}
 
-	// --- Szenario 2: Einteilung in 3er-Gruppen ---
-	fmt.Println()
-	fmt.Println(strings.Repeat("=", 62))
-	fmt.Println("=== Einteilung in 3er-Gruppen (eventuell mit Anpassung).")
-	bestGroups3er := [][]string{}
-	bestUngrouped3er := []string{}
-	maxGroupedStudents3er := -1
+	if options.Command == "pruefen" { // Nur prüfen, keine Gruppen bilden.
+		if hintsFound && options.Batch { // Die Hinweise nennen Gründe, warum keine vollständige Einteilung möglich ist.
+			os.Exit(exitInfeasible)
+		}
+		return
+	}
 
-	for i := 0; i < attempts; i++ {
-		currentGroups, currentUngrouped := formThreePersonGroups(config.Schuelerliste, config.Constraints)
-		currentGroupedStudents := len(config.Schuelerliste) - len(currentUngrouped)
+	// Anzahl der Versuche, Gruppen zu bilden (wegen Zufälligkeit), verteilt auf mehrere Worker.
+	search := searchSettings{Attempts: options.Attempts, Workers: options.Workers, TimeBudget: options.TimeBudget}
+	seed := options.Seed // Startwert für alle Zufallsentscheidungen.
+	if seed == 0 {       // Ohne '-seed' wird ein kurzer, zufälliger Startwert gewählt.
+		seed = rand.Int63n(1000000) + 1
+	}
 
-		if currentGroupedStudents > maxGroupedStudents3er {
-			maxGroupedStudents3er = currentGroupedStudents
-			bestGroups3er = currentGroups
-			bestUngrouped3er = currentUngrouped
-			if len(currentUngrouped) == 0 {
-				break
+	if options.Command == "rotation" { // Rotationsplan statt einzelner Einteilungen.
+		var rounds []scenarioResult // Alle Runden aller Szenarien (für den Exit-Code).
+		for i, scenario := range scenarios {
+			for _, round := range runRotation(config, rules, scenario, options.Rounds, search, seed) {
+				round.Hints = hints[i]
+				rounds = append(rounds, round)
 			}
 		}
-	}
-	// Ausgabe der Ergebnisse für Szenario 2.
-	if len(bestGroups3er) == 0 && len(bestUngrouped3er) > 0 {
-		fmt.Println("❌ Es konnten keine gültigen 3er-Gruppen gebildet werden. Alle Schüler sind ungepaart.")
-	} else if len(bestGroups3er) == 0 {
-		fmt.Println("❌ Es konnten keine 3er-Gruppen gebildet werden.")
-	} else {
-		for i, group := range bestGroups3er {
-			fmt.Printf("Gruppe %d (%d Personen): %v\n", i+1, len(group), group)
+		fmt.Println()
+		fmt.Println(strings.Repeat("=", 62))
+		export := &exportData{Config: config, Results: rounds, Warnings: warnings, MaxScore: rules.maxScore,
+			Title: options.Title, GroupNames: options.GroupNames}
+		if err := writeExport(options, export, exportOutput); err != nil {
+			fmt.Println(err)
+			os.Exit(exitError)
+		}
+		if options.Batch { // Ohne Rückfragen: Das Ergebnis steht im Exit-Code.
+			os.Exit(exitCodeFor(rounds))
 		}
+		fmt.Println("\nDrücke Enter, um das Programm zu beenden...")
+		fmt.Scanln() // Wartet auf die Eingabe der Enter-Taste durch den Benutzer.
+		return
 	}
-	if len(bestUngrouped3er) > 0 {
-		fmt.Printf("❗️ Ungruppierte Schüler: %v\n", bestUngrouped3er)
-	} else {
-		fmt.Println("✅ Alle Schüler wurden erfolgreich in 3er-Gruppen eingeteilt!")
+
+	var results []scenarioResult // Die Ergebnisse aller Szenarien (zum Speichern im Verlauf).
+	for i, scenario := range scenarios {
+		result := runScenario(config, rules, scenario, search, options.ExactFirst, seed)
+		result.Hints = hints[i]
+		results = append(results, result)
 	}
 
-	// --- Szenario 3: Einteilung in 4er-Gruppen ---
 	fmt.Println()
 	fmt.Println(strings.Repeat("=", 62))
-	fmt.Println("=== Einteilung in 4er-Gruppen (eventuell mit Anpassung).")
-	bestGroups4er := [][]string{}
-	bestUngrouped4er := []string{}
-	maxGroupedStudents4er := -1
-
-	for i := 0; i < attempts; i++ {
-		currentGroups, currentUngrouped := formFourPersonGroups(config.Schuelerliste, config.Constraints)
-		currentGroupedStudents := len(config.Schuelerliste) - len(currentUngrouped)
+	fmt.Println()
 
-		if currentGroupedStudents > maxGroupedStudents4er {
-			maxGroupedStudents4er = currentGroupedStudents
-			bestGroups4er = currentGroups
-			bestUngrouped4er = currentUngrouped
-			if len(currentUngrouped) == 0 {
-				break
-			}
-		}
-	}
-	// Ausgabe der Ergebnisse für Szenario 3.
-	if len(bestGroups4er) == 0 && len(bestUngrouped4er) > 0 {
-		fmt.Println("❌ Es konnten keine gültigen 4er-Gruppen gebildet werden. Alle Schüler sind ungepaart.")
-	} else if len(bestGroups4er) == 0 {
-		fmt.Println("❌ Es konnten keine 4er-Gruppen gebildet werden.")
-	} else {
-		for i, group := range bestGroups4er {
-			fmt.Printf("Gruppe %d (%d Personen): %v\n", i+1, len(group), group)
-		}
+	// Zusätzlich zur Konsolenausgabe: Export im gewählten Format (z.B. '-format csv').
+	export := &exportData{Config: config, Results: results, Warnings: warnings, MaxScore: rules.maxScore,
+		Title: options.Title, GroupNames: options.GroupNames}
+	if err := writeExport(options, export, exportOutput); err != nil {
+		fmt.Println(err)
+		os.Exit(exitError)
 	}
-	if len(bestUngrouped4er) > 0 {
-		fmt.Printf("❗️ Ungruppierte Schüler: %v\n", bestUngrouped4er)
-	} else {
-		fmt.Println("✅ Alle Schüler wurden erfolgreich in 4er-Gruppen eingeteilt!")
+
+	if options.Batch { // Ohne Rückfragen: Das Ergebnis steht im Exit-Code.
+		os.Exit(exitCodeFor(results))
 	}
-	
-	fmt.Println()
-	fmt.Println(strings.Repeat("=", 62))
-	fmt.Println()
 
 	// Diese Zeilen sind dafür da, das Konsolenfenster auf Windows offen zu halten,
 	// 	wenn das Programm per Doppelklick gestartet wird.
-	fmt.Println("\nDrücke Enter, um das Programm zu beenden...")
-	fmt.Scanln() // Wartet auf die Eingabe der Enter-Taste durch den Benutzer.
+	// Gleichzeitig kann eine Einteilung angenommen und im Verlauf gespeichert werden.
+	fmt.Println("Soll eine Einteilung im Verlauf gespeichert werden, damit sich Paare beim nächsten Mal nicht wiederholen?")
+	for i, result := range results {
+		fmt.Printf("  %d = %s\n", i+1, result.Scenario.title())
+	}
+	fmt.Println("\nGib die Nummer ein oder drücke Enter, um das Programm ohne Speichern zu beenden...")
+	var answer string
+	fmt.Scanln(&answer) // Wartet auf die Eingabe (oder nur die Enter-Taste) durch den Benutzer.
+	if choice, convErr := strconv.Atoi(strings.TrimSpace(answer)); convErr == nil && choice >= 1 && choice <= len(results) {
+		chosen := results[choice-1]
+		history.addSession(chosen.Scenario.title(), chosen.Groups)
+		if err := saveHistory(historyPath, history); err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("✅ Einteilung in %s im Verlauf gespeichert: %s\n", chosen.Scenario.title(), historyPath)
+		}
+	}
 }
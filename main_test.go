@@ -0,0 +1,125 @@
+package main // Tests für die Gruppenbildung in main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"       // Für die Namen der Test-Schüler.
+	"math/rand" // Für eine feste Zufallsquelle pro Testfall.
+	"reflect"   // Zum Vergleichen der Gruppengrößen.
+	"sort"      // Damit die Gruppengrößen unabhängig von der Reihenfolge verglichen werden.
+	"testing"   // Test-Framework von Go.
+)
+
+// ############################################################################################
+// testStudents erstellt 'count' Schüler mit den Namen "S1", "S2", ...
+func testStudents(count int) []string {
+	students := make([]string, count)
+	for i := range students {
+		students[i] = fmt.Sprintf("S%d", i+1)
+	}
+	return students
+}
+
+// testRules erstellt die Regeln für eine Klasse ohne Wünsche.
+func testRules(students []string, constraints map[string][]string, together map[string][]string) *groupRules {
+	return newGroupRules(&Config{Schuelerliste: students, Constraints: constraints, Together: together})
+}
+
+// groupSizes gibt die Größen der Gruppen absteigend sortiert zurück.
+func groupSizes(groups [][]string) []int {
+	sizes := make([]int, len(groups))
+	for i, group := range groups {
+		sizes[i] = len(group)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	return sizes
+}
+
+// ############################################################################################
+// TestFormGroupsSizes prüft, wie formGroups Restschüler ohne Konflikte auf die Gruppen verteilt.
+func TestFormGroupsSizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		scenario groupScenario
+		want     []int
+	}{
+		{"2er ohne Rest", 6, groupScenario{TargetSize: 2, MinSize: 2, MaxSize: 3}, []int{2, 2, 2}},
+		{"2er mit einem Restschüler", 7, groupScenario{TargetSize: 2, MinSize: 2, MaxSize: 3}, []int{3, 2, 2}},
+		{"3er ohne Rest", 9, groupScenario{TargetSize: 3, MinSize: 2, MaxSize: 4}, []int{3, 3, 3}},
+		{"3er mit einem Restschüler", 10, groupScenario{TargetSize: 3, MinSize: 2, MaxSize: 4}, []int{4, 3, 3}},
+		{"3er mit Restgruppe", 11, groupScenario{TargetSize: 3, MinSize: 2, MaxSize: 4}, []int{3, 3, 3, 2}},
+		{"4er mit Restgruppe", 11, groupScenario{TargetSize: 4, MinSize: 3, MaxSize: 5}, []int{4, 4, 3}},
+		{"4er mit einem Restschüler", 13, groupScenario{TargetSize: 4, MinSize: 3, MaxSize: 5}, []int{5, 4, 4}},
+		{"5er mit zwei Restschülern", 12, groupScenario{TargetSize: 5, MinSize: 4, MaxSize: 6}, []int{6, 6}},
+		{"weniger Schüler als Mindestgröße", 1, groupScenario{TargetSize: 2, MinSize: 2, MaxSize: 3}, []int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			students := testStudents(test.count)
+			groups, ungrouped := test.scenario.form(students, testRules(students, nil, nil), rand.New(rand.NewSource(1)))
+			if got := groupSizes(groups); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Gruppengrößen = %v, erwartet %v", got, test.want)
+			}
+			if grouped := len(students) - len(ungrouped); grouped != sum(test.want) {
+				t.Errorf("%d Schüler eingeteilt, erwartet %d (ungruppiert: %v)", grouped, sum(test.want), ungrouped)
+			}
+		})
+	}
+}
+
+// TestFormGroupsRules prüft, dass formGroups Konflikte einhält und Pflichtpartner nie trennt.
+func TestFormGroupsRules(t *testing.T) {
+	students := testStudents(12)
+	constraints := map[string][]string{"S1": {"S2", "S3"}, "S2": {"S1"}, "S3": {"S1"}}
+	together := map[string][]string{"S4": {"S5"}, "S5": {"S4"}}
+	rules := testRules(students, constraints, together)
+	contains := func(group []string, student string) bool {
+		for _, member := range group {
+			if member == student {
+				return true
+			}
+		}
+		return false
+	}
+	for seed := int64(1); seed <= 20; seed++ {
+		groups, _ := formGroups(students, 3, 2, 4, rules, rand.New(rand.NewSource(seed)))
+		for _, group := range groups {
+			if !isValidGroup(group, rules) {
+				t.Errorf("Seed %d: ungültige Gruppe %v", seed, group)
+			}
+			if contains(group, "S4") != contains(group, "S5") {
+				t.Errorf("Seed %d: Pflichtpartner getrennt in %v", seed, group)
+			}
+		}
+	}
+}
+
+// TestMinimalSizeDeviation prüft die kleinste mögliche Abweichung der Gruppengrößen von der Zielgröße.
+func TestMinimalSizeDeviation(t *testing.T) {
+	tests := []struct {
+		studentCount, targetSize, minSize, maxSize int
+		want                                       int
+	}{
+		{24, 3, 2, 4, 0},
+		{10, 3, 2, 4, 1},
+		{11, 4, 3, 5, 1},
+		{5, 3, 3, 3, -1},
+		{7, 2, 2, 3, 1},
+	}
+	for _, test := range tests {
+		got := minimalSizeDeviation(test.studentCount, test.targetSize, test.minSize, test.maxSize)
+		if got != test.want {
+			t.Errorf("minimalSizeDeviation(%d, %d, %d, %d) = %d, erwartet %d",
+				test.studentCount, test.targetSize, test.minSize, test.maxSize, got, test.want)
+		}
+	}
+}
+
+// sum gibt die Summe der Zahlen zurück.
+func sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
@@ -0,0 +1,222 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"     // Zum Formatieren der Meldungen.
+	"sort"    // Für eine stabile Reihenfolge der Meldungen und Vorschläge.
+	"strings" // Zum Vergleichen der Namen ohne Groß-/Kleinschreibung.
+
+	"github.com/pelletier/go-toml" // Für die Position der Einträge in der Datei.
+)
+
+// ############################################################################################
+// maxNameSuggestions begrenzt die Anzahl der Vorschläge bei einem unbekannten Namen.
+const maxNameSuggestions = 3
+
+// ############################################################################################
+// recordPositions merkt sich, in welcher Zeile und Spalte jeder Schlüssel des Abschnitts steht
+// 		("" = Schlüssel ohne Abschnitt), damit Meldungen auf die Stelle in der Datei zeigen können.
+// Für Schlüssel mit einer Inline-Tabelle (z.B. "Tom" = { "Lea" = 2 }) kennt go-toml keine Stelle,
+// 		sie wird dann im Text der Datei 'content' gesucht.
+func recordPositions(positions map[string]toml.Position, tree *toml.Tree, section string, content string) {
+	for _, key := range tree.Keys() {
+		position := tree.GetPositionPath([]string{key})
+		if position.Invalid() {
+			position = findKeyPosition(content, section, key)
+		}
+		positions[positionKey(section, key)] = position
+	}
+}
+
+// findKeyPosition sucht die Zeile, in der 'key' im Abschnitt 'section' definiert wird.
+// Gibt eine ungültige Position zurück, wenn der Schlüssel nicht gefunden wird.
+func findKeyPosition(content string, section string, key string) toml.Position {
+	currentSection := ""
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") { // Neuer Abschnitt, z.B. [zusammen] oder [[ausgleich]].
+			currentSection = strings.TrimSpace(strings.Trim(trimmed, "[]"))
+			continue
+		}
+		if currentSection != section {
+			continue
+		}
+		for _, written := range []string{fmt.Sprintf("%q", key), "'" + key + "'", key} { // Mit und ohne Anführungszeichen.
+			rest, found := strings.CutPrefix(trimmed, written)
+			if found && strings.HasPrefix(strings.TrimSpace(rest), "=") {
+				indent := len([]rune(line)) - len([]rune(strings.TrimLeft(line, " \t")))
+				return toml.Position{Line: i + 1, Col: indent + 1}
+			}
+		}
+	}
+	return toml.Position{}
+}
+
+// positionKey ist der Schlüssel für 'validationReport.Positions'.
+func positionKey(section string, key string) string {
+	return section + "\x00" + key
+}
+
+// ############################################################################################
+// checkStudentNames prüft alle Namen in den Einschränkungen gegen die Schülerliste
+// 		und trägt die Probleme in den Prüfbericht 'config.Report' ein:
+// 1. Doppelte Namen in der Schülerliste.
+// 2. Unbekannte Namen in den Konflikten, Pflichtpartnern und Wünschen. Solche Einschränkungen
+// 		gelten sonst einfach nicht, z.B. bei "Jonas" statt "Jonas M.".
+// Zu unbekannten Namen werden ähnliche Namen aus der Schülerliste vorgeschlagen (siehe suggestNames).
+func checkStudentNames(config *Config) {
+	if len(config.Schuelerliste) == 0 {
+		return // Ohne Schülerliste gibt es nichts zu vergleichen.
+	}
+	report := config.Report
+
+	// 1. Doppelte Namen in der Schülerliste.
+	count := make(map[string]int)
+	inClass := make(map[string]bool)
+	for _, student := range config.Schuelerliste {
+		count[student]++
+		inClass[student] = true
+	}
+	for _, student := range uniqueNames(config.Schuelerliste) {
+		if count[student] > 1 {
+			report.add(report.position("", "schuelerliste"), issueDuplicate,
+				"'%s' steht %d Mal in der Schülerliste und wird nur einmal berücksichtigt", student, count[student])
+		}
+	}
+
+	// 2. Unbekannte Namen, je Abschnitt nur einmal gemeldet.
+	sections := []struct {
+		Name    string              // Abschnitt in der Datei.
+		Label   string              // Beschreibung für die Meldung.
+		Entries map[string][]string // Schüler → genannte Mitschüler.
+	}{
+		{conflictsSection, "in den Konflikten", config.Constraints},
+		{togetherSection, fmt.Sprintf("im Abschnitt [%s]", togetherSection), config.Together},
+		{avoidSection, fmt.Sprintf("im Abschnitt [%s]", avoidSection), weightedNames(config.Avoid)},
+		{preferSection, fmt.Sprintf("im Abschnitt [%s]", preferSection), weightedNames(config.Prefer)},
+	}
+	for _, section := range sections {
+		reported := make(map[string]bool)
+		reportUnknown := func(name string, student string) { // Meldet 'name', gefunden beim Eintrag von 'student'.
+			if inClass[name] || reported[name] {
+				return
+			}
+			reported[name] = true
+			message := fmt.Sprintf("'%s' %s steht nicht in der Schülerliste, die Einschränkung gilt nicht", name, section.Label)
+			if suggestions := suggestNames(name, config.Schuelerliste); len(suggestions) > 0 {
+				message += fmt.Sprintf(". Meintest du %s?", strings.Join(quoteNames(suggestions), " oder "))
+			}
+			report.add(report.position(section.Name, student), issueUnknownName, "%s", message)
+		}
+		for _, student := range sortedKeys(section.Entries) { // Zuerst die Schlüssel: Dort steht der Name selbst.
+			reportUnknown(student, student)
+		}
+		for _, student := range sortedKeys(section.Entries) {
+			others := append([]string{}, section.Entries[student]...)
+			sort.Strings(others)
+			for _, name := range others {
+				reportUnknown(name, student)
+			}
+		}
+	}
+}
+
+// weightedNames wandelt einen Abschnitt mit Gewichten in Schüler → genannte Mitschüler um.
+func weightedNames(weighted map[string]map[string]int) map[string][]string {
+	result := make(map[string][]string)
+	for student, weights := range weighted {
+		result[student] = []string{}
+		for other := range weights {
+			result[student] = append(result[student], other)
+		}
+	}
+	return result
+}
+
+// uniqueNames gibt die Namen ohne Wiederholungen zurück (Reihenfolge des ersten Vorkommens).
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
+
+// quoteNames setzt jeden Namen in Anführungszeichen (in der gegebenen Reihenfolge).
+func quoteNames(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	return quoted
+}
+
+// ############################################################################################
+// suggestNames sucht Namen aus der Schülerliste, die dem unbekannten Namen ähnlich sind:
+// 		Namen mit kleinem Editierabstand (Tippfehler, Groß-/Kleinschreibung) oder Namen,
+// 		die mit dem unbekannten Namen beginnen (z.B. "Jonas" → "Jonas M.").
+// Die ähnlichsten Namen kommen zuerst.
+func suggestNames(name string, candidates []string) []string {
+	lowerName := strings.ToLower(strings.TrimSpace(name))
+	maxDistance := len([]rune(lowerName)) / 3 // Bei kurzen Namen nur wenige Tippfehler erlauben.
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	type suggestion struct {
+		Name     string // Vorgeschlagener Name.
+		Distance int    // Editierabstand zum unbekannten Namen.
+	}
+	var suggestions []suggestion
+	for _, candidate := range uniqueNames(candidates) {
+		lowerCandidate := strings.ToLower(candidate)
+		distance := editDistance(lowerName, lowerCandidate)
+		isPrefix := lowerName != "" && (strings.HasPrefix(lowerCandidate, lowerName+" ") || strings.HasPrefix(lowerName, lowerCandidate+" "))
+		if distance <= maxDistance || isPrefix {
+			suggestions = append(suggestions, suggestion{Name: candidate, Distance: distance})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+
+	var names []string
+	for i := 0; i < len(suggestions) && i < maxNameSuggestions; i++ {
+		names = append(names, suggestions[i].Name)
+	}
+	return names
+}
+
+// editDistance berechnet den Editierabstand (Damerau-Levenshtein): die kleinste Anzahl eingefügter,
+// 		gelöschter, ersetzter oder vertauschter Zeichen, um 'a' in 'b' umzuwandeln ("Bne" → "Ben" = 1).
+func editDistance(a string, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	distance := make([][]int, len(runesA)+1) // distance[i][j]: Abstand der ersten i bzw. j Zeichen.
+	for i := range distance {
+		distance[i] = make([]int, len(runesB)+1)
+		distance[i][0] = i
+	}
+	for j := range distance[0] {
+		distance[0][j] = j
+	}
+	for i := 1; i <= len(runesA); i++ {
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			distance[i][j] = min(distance[i-1][j]+1, distance[i][j-1]+1, distance[i-1][j-1]+cost)
+			if i > 1 && j > 1 && runesA[i-1] == runesB[j-2] && runesA[i-2] == runesB[j-1] { // Vertauschte Nachbarn.
+				distance[i][j] = min(distance[i][j], distance[i-2][j-2]+1)
+			}
+		}
+	}
+	return distance[len(runesA)][len(runesB)]
+}
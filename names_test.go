@@ -0,0 +1,61 @@
+package main // Tests für die Prüfung der Namen in names.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"reflect" // Zum Vergleichen der Vorschläge.
+	"testing" // Test-Framework von Go.
+)
+
+// ############################################################################################
+// TestEditDistance prüft den Editierabstand mit Einfügen, Löschen, Ersetzen und Vertauschen.
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"Ben", "Ben", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"Ben", "Bne", 1},       // Vertauschte Nachbarn.
+		{"Ben", "Benn", 1},      // Eingefügt.
+		{"Carla", "Crla", 1},    // Gelöscht.
+		{"Jürgen", "Jurgen", 1}, // Umlaute zählen als ein Zeichen.
+		{"kitten", "sitting", 3},
+		{"ben", "Ben", 1}, // Groß-/Kleinschreibung gleicht suggestNames an, nicht editDistance.
+	}
+	for _, test := range tests {
+		if got := editDistance(test.a, test.b); got != test.want {
+			t.Errorf("editDistance(%q, %q) = %d, erwartet %d", test.a, test.b, got, test.want)
+		}
+		if got := editDistance(test.b, test.a); got != test.want {
+			t.Errorf("editDistance(%q, %q) = %d, erwartet %d (umgekehrt)", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+// TestSuggestNames prüft die Vorschläge für unbekannte Namen und ihre Reihenfolge.
+func TestSuggestNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		unknown    string
+		candidates []string
+		want       []string
+	}{
+		{"Tippfehler", "Bne", []string{"Anna", "Ben", "Carla"}, []string{"Ben"}},
+		{"Groß-/Kleinschreibung", "anna", []string{"Anna", "Ben"}, []string{"Anna"}},
+		{"Vorname ohne Nachname", "Jonas", []string{"Jonas M.", "Anna", "Jonas K."}, []string{"Jonas K.", "Jonas M."}},
+		{"Nachname zu viel", "Jonas M.", []string{"Jonas", "Anna"}, []string{"Jonas"}},
+		{"ähnlichste zuerst, höchstens drei", "Lena", []string{"Lina", "Leni", "Lena", "Lea"}, []string{"Lena", "Lea", "Leni"}},
+		{"doppelte Namen nur einmal", "Bem", []string{"Ben", "Ben"}, []string{"Ben"}},
+		{"nichts Ähnliches", "Xaver", []string{"Anna", "Ben"}, nil},
+		{"kurze Namen nur ein Tippfehler", "Al", []string{"Ben", "Eli"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := suggestNames(test.unknown, test.candidates); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("suggestNames(%q) = %v, erwartet %v", test.unknown, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"encoding/csv"   // Zum Schreiben der CSV-Datei.
+	"encoding/json"  // Zum Schreiben der JSON-Datei.
+	"fmt"            // Für Fehlermeldungen.
+	"io"             // Damit der Export in eine Datei oder nach stdout schreiben kann.
+	"os"             // Zum Erstellen der Exportdatei.
+	"path/filepath"  // Um die Exportdatei neben 'klasse.toml' abzulegen.
+	"sort"           // Für eine stabile Reihenfolge der Merkmale.
+	"strconv"        // Zum Umwandeln von Zahlen in CSV-Felder.
+	"strings"        // Zum Ableiten des Dateinamens.
+	"text/tabwriter" // Für die ausgerichtete Text-Tabelle.
+)
+
+// ############################################################################################
+// exportFormat beschreibt ein Ausgabeformat, das zusätzlich zur Konsolenausgabe geschrieben wird.
+type exportFormat struct {
+	Extension string                                    // Dateiendung der Exportdatei (z.B. ".csv").
+	Write     func(w io.Writer, data *exportData) error // Schreibt alle Ergebnisse im Format.
+}
+
+// exportFormats enthält alle Ausgabeformate außer "text" (der normalen Konsolenausgabe).
+var exportFormats = map[string]exportFormat{
+	"csv":      {Extension: ".csv", Write: writeCSV},
+	"json":     {Extension: ".json", Write: writeJSON},
+	"html":     {Extension: ".html", Write: writeHTML},
+	"markdown": {Extension: ".md", Write: writeMarkdown},
+	"tabelle":  {Extension: ".txt", Write: writeTable},
+}
+
+// exportData enthält alles, was exportiert wird.
+type exportData struct {
+	Config     *Config          // Für die Merkmale der Schüler.
+	Results    []scenarioResult // Die Ergebnisse aller Szenarien bzw. Runden.
+	Warnings   []string         // Warnungen der Prüfungen (z.B. unsymmetrische Konflikte).
+	MaxScore   int              // Bestmögliche Bewertung (siehe groupRules.maxScore).
+	Title      string           // Überschrift für den Aushang ('-titel').
+	GroupNames []string         // Eigene Namen der Gruppen für den Aushang ('-namen').
+}
+
+// stdoutPath ist der Wert von '-ausgabe', mit dem der Export nach stdout geschrieben wird.
+const stdoutPath = "-"
+
+// ############################################################################################
+// exportPathFor gibt den Standardpfad der Exportdatei zurück.
+// Sie liegt neben der Konfigurationsdatei, z.B. 'klasse.toml' → 'klasse-gruppen.csv'.
+func exportPathFor(configPath string, extension string) string {
+	base := strings.TrimSuffix(filepath.Base(configPath), filepath.Ext(configPath))
+	return filepath.Join(filepath.Dir(configPath), base+"-gruppen"+extension)
+}
+
+// ############################################################################################
+// redirectConsoleForExport leitet die normale Konsolenausgabe nach stderr um, wenn der Export
+// 		nach stdout geschrieben wird. So enthält stdout nur den Export (z.B. für '> gruppen.csv').
+// Zurückgegeben wird das ursprüngliche stdout, in das der Export geschrieben wird.
+func redirectConsoleForExport(options *cliOptions) io.Writer {
+	stdout := os.Stdout
+	if options.Format != "text" && options.OutputPath == stdoutPath {
+		os.Stdout = os.Stderr // fmt.Print* schreibt ab jetzt nach stderr.
+	}
+	return stdout
+}
+
+// ############################################################################################
+// writeExport schreibt die Ergebnisse im gewählten Ausgabeformat in eine Datei oder nach 'stdout'.
+// Ohne '-ausgabe' liegt die Datei neben der Konfigurationsdatei.
+// Beim Format "text" gibt es keinen Export, die Ergebnisse stehen bereits auf der Konsole.
+func writeExport(options *cliOptions, data *exportData, stdout io.Writer) error {
+	format, ok := exportFormats[options.Format]
+	if !ok {
+		return nil
+	}
+	if options.OutputPath == stdoutPath {
+		return format.Write(stdout, data)
+	}
+
+	path := options.OutputPath
+	if path == "" {
+		path = exportPathFor(data.Config.Path, format.Extension)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("❌ Fehler beim Erstellen der Exportdatei '%s': %w", path, err)
+	}
+	if err := format.Write(file, data); err != nil {
+		file.Close()
+		return fmt.Errorf("❌ Fehler beim Schreiben der Exportdatei '%s': %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("❌ Fehler beim Schreiben der Exportdatei '%s': %w", path, err)
+	}
+	fmt.Printf("✅ Ergebnisse als %s gespeichert: %s\n", strings.ToUpper(options.Format), path)
+	return nil
+}
+
+// ############################################################################################
+// groupName gibt den Namen der Gruppe mit dem Index 'index' zurück.
+// Eigene Gruppennamen ('-namen') werden der Reihe nach vergeben, danach heißen die Gruppen "Gruppe N".
+func groupName(index int, names []string) string {
+	if index < len(names) && names[index] != "" {
+		return names[index]
+	}
+	return fmt.Sprintf("Gruppe %d", index+1)
+}
+
+// ############################################################################################
+// attributeKeys gibt alle Merkmale zurück, die in der Schülerliste vorkommen (sortiert).
+func attributeKeys(config *Config) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, attributes := range config.Attributes {
+		for key := range attributes {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ############################################################################################
+// writeCSV schreibt eine Zeile pro Schüler: Szenario, Gruppennummer, Gruppengröße, Name und alle Merkmale.
+// Ungruppierte Schüler stehen am Ende ihres Szenarios mit leerer Gruppennummer.
+// Als Trennzeichen dient ';', damit die Datei in einer deutschen Tabellenkalkulation direkt passt.
+func writeCSV(w io.Writer, data *exportData) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	keys := attributeKeys(data.Config)
+	header := append([]string{"Szenario", "Gruppe", "Größe", "Name"}, keys...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	row := func(label, group, size, student string) error {
+		record := []string{label, group, size, student}
+		for _, key := range keys {
+			record = append(record, data.Config.Attributes[student][key])
+		}
+		return writer.Write(record)
+	}
+	for _, result := range data.Results {
+		for i, group := range result.Groups { // Dieselben Gruppen wie "Gruppe %d (%d Personen)" auf der Konsole.
+			for _, student := range group {
+				if err := row(result.label(), strconv.Itoa(i+1), strconv.Itoa(len(group)), student); err != nil {
+					return err
+				}
+			}
+		}
+		for _, student := range result.Ungrouped {
+			if err := row(result.label(), "", "", student); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ############################################################################################
+// jsonResult ist ein Szenario (bzw. eine Runde) in der JSON-Ausgabe.
+type jsonResult struct {
+	Scenario   string     `json:"szenario"`        // Bezeichnung, z.B. "3er-Gruppen" oder "Runde 2 (4 Gruppen)".
+	Round      int        `json:"runde,omitempty"` // Nummer der Runde im Rotationsplan.
+	Groups     [][]string `json:"gruppen"`         // Die gebildeten Gruppen.
+	Ungrouped  []string   `json:"ungruppiert"`     // Schüler ohne Gruppe.
+	Complete   bool       `json:"vollstaendig"`    // true, wenn alle Schüler eingeteilt sind.
+	Infeasible bool       `json:"unmoeglich"`      // true, wenn eine vollständige Einteilung nachweislich unmöglich ist.
+	Seed       int64      `json:"seed"`            // Startwert, mit dem das Ergebnis wiederholt werden kann.
+	Attempts   int        `json:"versuche"`        // Anzahl durchgeführter Versuche der Zufallssuche.
+	Score      int        `json:"bewertung"`       // Bewertung der Einteilung.
+	MaxScore   int        `json:"beste_bewertung"` // Bestmögliche Bewertung.
+	Hints      []string   `json:"hinweise"`        // Hinweise der Analyse für dieses Szenario.
+}
+
+// jsonExport ist der Inhalt der JSON-Ausgabe.
+type jsonExport struct {
+	ConfigPath string       `json:"konfiguration"` // Pfad der Konfigurationsdatei.
+	Students   []string     `json:"schueler"`      // Alle Schüler der Klasse.
+	Warnings   []string     `json:"warnungen"`     // Warnungen der Prüfungen.
+	Results    []jsonResult `json:"szenarien"`     // Alle Szenarien bzw. Runden.
+}
+
+// ############################################################################################
+// writeJSON schreibt alle Ergebnisse als JSON, z.B. für eigene Auswertungen oder ein Dashboard.
+// Leere Listen werden als [] statt null geschrieben, damit andere Programme sie einfacher lesen.
+func writeJSON(w io.Writer, data *exportData) error {
+	export := jsonExport{
+		ConfigPath: data.Config.Path,
+		Students:   nonNil(data.Config.Schuelerliste),
+		Warnings:   nonNil(data.Warnings),
+		Results:    []jsonResult{},
+	}
+	for _, result := range data.Results {
+		groups := result.Groups
+		if groups == nil {
+			groups = [][]string{}
+		}
+		export.Results = append(export.Results, jsonResult{
+			Scenario:   result.label(),
+			Round:      result.Round,
+			Groups:     groups,
+			Ungrouped:  nonNil(result.Ungrouped),
+			Complete:   len(result.Ungrouped) == 0,
+			Infeasible: result.Infeasible,
+			Seed:       result.Seed,
+			Attempts:   result.Attempts,
+			Score:      result.Score,
+			MaxScore:   data.MaxScore,
+			Hints:      nonNil(result.Hints),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false) // Namen wie "Anna & Ben" unverändert lassen.
+	return encoder.Encode(export)
+}
+
+// nonNil gibt eine leere Liste statt nil zurück (für [] statt null im JSON).
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// ############################################################################################
+// writeMarkdown schreibt pro Szenario eine Überschrift und eine Markdown-Tabelle,
+// 		z.B. zum Einfügen in die Lernplattform.
+func writeMarkdown(w io.Writer, data *exportData) error {
+	escape := strings.NewReplacer("|", "\\|") // Ein '|' im Namen würde sonst die Tabelle zerlegen.
+	var sb strings.Builder
+	if data.Title != "" {
+		sb.WriteString(fmt.Sprintf("# %s\n\n", data.Title))
+	}
+	for _, result := range data.Results {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", result.label()))
+		sb.WriteString("| Gruppe | Personen | Namen |\n")
+		sb.WriteString("| --- | ---: | --- |\n")
+		for i, group := range result.Groups {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %s |\n",
+				escape.Replace(groupName(i, data.GroupNames)), len(group), escape.Replace(strings.Join(group, ", "))))
+		}
+		if len(result.Ungrouped) > 0 {
+			sb.WriteString(fmt.Sprintf("\n**Ohne Gruppe:** %s\n", strings.Join(result.Ungrouped, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// ############################################################################################
+// writeTable schreibt pro Szenario eine ausgerichtete Text-Tabelle, z.B. für E-Mails.
+// Die Spalten werden mit tabwriter ausgerichtet, der auch Umlaute richtig zählt.
+func writeTable(w io.Writer, data *exportData) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if data.Title != "" {
+		fmt.Fprintf(table, "%s\n%s\n\n", data.Title, strings.Repeat("=", len([]rune(data.Title))))
+	}
+	for _, result := range data.Results {
+		fmt.Fprintf(table, "%s\n\n", result.label())
+		fmt.Fprintln(table, "Gruppe\tPersonen\tNamen")
+		fmt.Fprintln(table, "------\t--------\t-----")
+		for i, group := range result.Groups {
+			fmt.Fprintf(table, "%s\t%d\t%s\n", groupName(i, data.GroupNames), len(group), strings.Join(group, ", "))
+		}
+		if len(result.Ungrouped) > 0 {
+			fmt.Fprintf(table, "\nOhne Gruppe: %s\n", strings.Join(result.Ungrouped, ", "))
+		}
+		fmt.Fprintln(table)
+	}
+	return table.Flush()
+}
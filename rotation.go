@@ -0,0 +1,221 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"       // Für die Ausgabe des Rotationsplans.
+	"math/rand" // Für die Zufallsquelle des Plans (siehe '-seed').
+	"sort"      // Für eine stabile Reihenfolge der wiederholten Paare.
+	"strings"   // Für Trennlinien in der Ausgabe.
+	"time"      // Für das Zeitbudget ('-zeit').
+)
+
+// ############################################################################################
+// rotationRestarts legt fest, wie oft ein ganzer Rotationsplan neu aufgebaut wird.
+// Behalten wird der Plan mit den wenigsten wiederholten Paaren.
+// Mit Zeitbudget ('-zeit') wird stattdessen neu aufgebaut, bis die Zeit abgelaufen ist.
+const rotationRestarts = 20
+
+// ############################################################################################
+// buildRotation erstellt einen Rotationsplan mit 'rounds' Einteilungen nach demselben Szenario
+// 		(ähnlich dem "Social Golfer Problem"): Niemand soll zweimal mit derselben Person arbeiten.
+// Jede Runde wird zuerst wie eine einzelne Einteilung gesucht (findBestRandomGrouping); dabei zählen
+// 		alle Paare aus den vorherigen Runden (und aus dem Verlauf) als wiederholte Paare.
+// Danach wird jede Runde gegen alle anderen Runden durch Tauschen verbessert (improveBySwaps),
+// 		bis sich nichts mehr ändert.
+// Die Constraints und alle anderen Regeln gelten in jeder Runde unverändert.
+// Alle Zufallsentscheidungen kommen aus 'rng', damit ein Plan mit demselben Seed wiederholt werden kann.
+func buildRotation(config *Config, rules *groupRules, scenario groupScenario, rounds int, search searchSettings, rng *rand.Rand) []scenarioResult {
+	scenario = rotationScenario(scenario, len(config.Schuelerliste))
+	var bestSchedule []scenarioResult
+	bestRepeats, bestUngrouped := -1, 0
+	budget := &exactBudget{} // Die exakte Suche teilt sich die Suchschritte über alle Runden und Neuaufbauten.
+
+	deadline := time.Now().Add(search.TimeBudget) // Nur mit Zeitbudget von Bedeutung.
+	roundSearch := search                          // Jede einzelne Runde sucht mit fester Anzahl Versuche.
+	roundSearch.TimeBudget = 0
+
+	for restart := 0; ; restart++ {
+		if search.TimeBudget > 0 && restart > 0 && time.Now().After(deadline) {
+			break
+		}
+		if search.TimeBudget <= 0 && restart >= rotationRestarts {
+			break
+		}
+
+		var schedule []scenarioResult
+		for round := 0; round < rounds; round++ {
+			roundRules := rotationRules(rules, schedule, -1) // Paare aus den bisherigen Runden.
+			groups, ungrouped, attempts := findBestRandomGrouping(config, roundRules, scenario, roundSearch, rng)
+			infeasible := false
+			if len(ungrouped) > 0 { // Wie in runScenario: Die exakte Suche findet eine Lösung, falls es eine gibt.
+				exactGroups, status := solveExact(config.Schuelerliste, scenario, roundRules, rng, budget)
+				if status == exactFound {
+					groups, ungrouped = exactGroups, nil
+				}
+				infeasible = status == exactInfeasible
+			}
+			schedule = append(schedule, scenarioResult{Scenario: scenario, Groups: improveBySwaps(groups, roundRules), Ungrouped: ungrouped, Infeasible: infeasible, Attempts: attempts})
+		}
+
+		// Jede Runde gegen alle anderen verbessern, solange sich noch etwas ändert.
+		for changed := true; changed; {
+			changed = false
+			for round := range schedule {
+				roundRules := rotationRules(rules, schedule, round)
+				before := scoreGrouping(schedule[round].Groups, roundRules)
+				schedule[round].Groups = improveBySwaps(schedule[round].Groups, roundRules)
+				if scoreGrouping(schedule[round].Groups, roundRules) > before {
+					changed = true
+				}
+			}
+		}
+
+		_, repeats := repeatedPairings(schedule)
+		ungrouped := 0
+		for _, round := range schedule {
+			ungrouped += len(round.Ungrouped)
+		}
+		if bestRepeats < 0 || ungrouped < bestUngrouped || (ungrouped == bestUngrouped && repeats < bestRepeats) {
+			bestSchedule, bestRepeats, bestUngrouped = schedule, repeats, ungrouped
+		}
+		if bestRepeats == 0 && bestUngrouped == 0 {
+			break // Besser geht es nicht.
+		}
+	}
+	return bestSchedule
+}
+
+// ############################################################################################
+// rotationRules gibt eine Kopie der Regeln zurück, in der alle Paare aus den Runden des Plans
+// 		(außer der Runde 'skip') zusätzlich zum Verlauf als wiederholte Paare zählen.
+func rotationRules(rules *groupRules, schedule []scenarioResult, skip int) *groupRules {
+	others := &groupingHistory{}
+	for round, result := range schedule {
+		if round != skip {
+			others.addSession(result.Scenario.title(), result.Groups)
+		}
+	}
+
+	roundRules := *rules
+	roundRules.repeatCounts = others.recentPairCounts(len(others.Sessions))
+	for studentA, counts := range rules.repeatCounts { // Paare aus dem Verlauf zählen weiterhin.
+		for studentB, count := range counts {
+			if roundRules.repeatCounts[studentA] == nil {
+				roundRules.repeatCounts[studentA] = make(map[string]int)
+			}
+			roundRules.repeatCounts[studentA][studentB] += count
+		}
+	}
+	return &roundRules
+}
+
+// ############################################################################################
+// improveBySwaps verbessert eine Einteilung, indem je zwei Schüler aus verschiedenen Gruppen
+// 		getauscht werden, solange das die Bewertung erhöht (z.B. weniger wiederholte Paare).
+// Getauscht wird nur, wenn beide Gruppen danach noch gültig sind (siehe isValidGroup).
+func improveBySwaps(groups [][]string, rules *groupRules) [][]string {
+	bestScore := scoreGrouping(groups, rules)
+	improved := true
+	for improved {
+		improved = false
+		for g := range groups {
+			for h := g + 1; h < len(groups); h++ {
+				for i := range groups[g] {
+					for j := range groups[h] {
+						groups[g][i], groups[h][j] = groups[h][j], groups[g][i]
+						if isValidGroup(groups[g], rules) && isValidGroup(groups[h], rules) {
+							if score := scoreGrouping(groups, rules); score > bestScore {
+								bestScore = score
+								improved = true
+								continue // Tausch behalten.
+							}
+						}
+						groups[g][i], groups[h][j] = groups[h][j], groups[g][i] // Tausch rückgängig machen.
+					}
+				}
+			}
+		}
+	}
+	return groups
+}
+
+// ############################################################################################
+// rotationScenario legt für einen Rotationsplan die Anzahl Gruppen fest.
+// Sonst würde die Suche in späteren Runden kleinere Gruppen bilden, weil diese weniger
+// 		Wiederholungen haben. So sind alle Runden gleich eingeteilt (siehe groupSizesForCount).
+func rotationScenario(scenario groupScenario, studentCount int) groupScenario {
+	if scenario.GroupCount > 0 {
+		return scenario
+	}
+	groupCount := studentCount / scenario.TargetSize
+	if groupCount < 1 {
+		groupCount = 1
+	}
+	return groupScenario{GroupCount: groupCount}
+}
+
+// ############################################################################################
+// repeatedPairings gibt alle Paare zurück, die im Rotationsplan mehr als einmal zusammen sind,
+// 		zusammen mit der Anzahl unvermeidbarer Wiederholungen (jede weitere Runde zählt einmal).
+func repeatedPairings(schedule []scenarioResult) ([]string, int) {
+	together := make(map[[2]string]int)
+	for _, round := range schedule {
+		for _, group := range round.Groups {
+			for i, studentA := range group {
+				for _, studentB := range group[i+1:] {
+					pair := [2]string{studentA, studentB}
+					if studentB < studentA { // Paar unabhängig von der Reihenfolge zählen.
+						pair = [2]string{studentB, studentA}
+					}
+					together[pair]++
+				}
+			}
+		}
+	}
+
+	var descriptions []string
+	repeats := 0
+	for pair, count := range together {
+		if count > 1 {
+			repeats += count - 1
+			descriptions = append(descriptions, fmt.Sprintf("'%s' & '%s' (%d×)", pair[0], pair[1], count))
+		}
+	}
+	sort.Strings(descriptions)
+	return descriptions, repeats
+}
+
+// ############################################################################################
+// runRotation berechnet einen Rotationsplan und gibt alle Runden sowie die
+// 		nicht vermeidbaren Wiederholungen auf der Konsole aus.
+func runRotation(config *Config, rules *groupRules, scenario groupScenario, rounds int, search searchSettings, seed int64) []scenarioResult {
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", 62))
+	fmt.Printf("=== Rotationsplan: %d Runden für %s (%s).\n", rounds, scenario.title(), rotationScenario(scenario, len(config.Schuelerliste)).title())
+
+	schedule := buildRotation(config, rules, scenario, rounds, search, rand.New(rand.NewSource(seed)))
+	for i := range schedule {
+		schedule[i].Seed = seed // Der ganze Plan wird mit demselben Seed wiederholt.
+		schedule[i].Round = i + 1
+		schedule[i].Score = scoreGrouping(schedule[i].Groups, rotationRules(rules, schedule, i))
+	}
+	for i, round := range schedule {
+		fmt.Printf("\n--- Runde %d\n", i+1)
+		for j, group := range round.Groups {
+			fmt.Printf("Gruppe %d (%d Personen): %v\n", j+1, len(group), group)
+		}
+		if len(round.Ungrouped) > 0 {
+			fmt.Printf("❗️ Ungruppierte Schüler: %v\n", round.Ungrouped)
+		}
+	}
+
+	fmt.Println()
+	pairs, repeats := repeatedPairings(schedule)
+	if repeats == 0 {
+		fmt.Println("✅ Niemand arbeitet in diesem Plan zweimal mit derselben Person.")
+	} else {
+		fmt.Printf("❗️ %d wiederholte Paarungen konnten nicht vermieden werden: %s\n", repeats, strings.Join(pairs, ", "))
+	}
+	fmt.Printf("ℹ️ %s\n", describeSeed(seed, search))
+	return schedule
+}
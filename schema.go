@@ -0,0 +1,409 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"           // Für die Meldungen und die neue Versionszeile.
+	"os"            // Zum Schreiben der umgestellten Datei und der Sicherung.
+	"path/filepath" // Für den Dateinamen in den Meldungen.
+	"sort"          // Damit die Schlüssel in der Reihenfolge der Datei verschoben werden.
+	"strings"       // Zum Zerlegen der Datei in Zeilen.
+	"time"          // Für die Einstellung 'zeit'.
+
+	"github.com/pelletier/go-toml" // Für die Fundstellen der Schlüssel in der Datei.
+)
+
+// ############################################################################################
+// Versionen des Dateiformats von 'klasse.toml':
+// Version 1 (ohne 'version'): Jeder Schlüssel ohne Abschnitt außer 'schuelerliste' ist ein Konflikt,
+// 		z.B. "Anna" = ["Ben"]. Dadurch konnte kein neuer Schlüssel dazukommen, ohne als Schüler zu gelten.
+// Version 2: Konflikte stehen im Abschnitt [konflikte], Voreinstellungen in [einstellungen].
+// 		Ohne Abschnitt sind nur noch die Schlüssel aus topLevelKeys erlaubt.
+// Dateien im alten Format werden beim Laden automatisch umgestellt (siehe migrateConfig) und mit
+// 		einer Sicherung der alten Fassung gespeichert (siehe saveMigratedConfig).
+const (
+	schemaVersionKey     = "version" // Schlüssel mit der Version des Dateiformats.
+	currentSchemaVersion = 2         // Version, die dieses Programm liest und schreibt.
+)
+
+// topLevelKeys sind die Schlüssel, die (ab Version 2) ohne Abschnitt stehen dürfen.
+var topLevelKeys = []string{schemaVersionKey, "schuelerliste", conflictPairsKey}
+
+// settingKeys sind die erlaubten Schlüssel in [einstellungen]. Sie heißen wie die Optionen beim Aufruf.
+var settingKeys = []string{"groesse", "gruppen", "versuche", "zeit", "exakt", "format", "titel", "namen"}
+
+// ############################################################################################
+// schemaVersionLine ist die Zeile, mit der neue und umgestellte Dateien beginnen.
+func schemaVersionLine() string {
+	return fmt.Sprintf("%s = %d # Version des Dateiformats, bitte nicht ändern.\n", schemaVersionKey, currentSchemaVersion)
+}
+
+// isTopLevelKey prüft, ob ein Schlüssel ohne Abschnitt stehen darf.
+func isTopLevelKey(key string) bool {
+	return containsString(topLevelKeys, key)
+}
+
+// ############################################################################################
+// readSettings liest den Abschnitt [einstellungen]. Die Werte sind bereits geprüft und haben den Typ
+// 		der gleichnamigen Option (int, bool, string, time.Duration oder []string).
+// Ungültige Werte kommen in den Prüfbericht und werden ignoriert.
+func readSettings(tree *toml.Tree, report *validationReport) map[string]interface{} {
+	settings := make(map[string]interface{})
+	section := tree.Get(settingsSection)
+	if section == nil {
+		return settings // Abschnitt ist optional.
+	}
+	table, ok := section.(*toml.Tree)
+	if !ok {
+		report.add(report.position("", settingsSection), issueWrongType, "'%s' muss ein Abschnitt [%s] sein, gefunden: %s", settingsSection, settingsSection, describeType(section))
+		return settings
+	}
+	where := fmt.Sprintf("[%s]", settingsSection)
+	checkKnownKeys(table, settingKeys, where, report)
+
+	for _, key := range settingKeys {
+		value := table.Get(key)
+		if value == nil {
+			continue
+		}
+		position := table.GetPositionPath([]string{key})
+		switch key {
+		case "groesse", "gruppen", "versuche": // Ganze Zahlen wie '-groesse 3'.
+			minimum := int64(0)
+			if key == "versuche" {
+				minimum = 1
+			}
+			number, isInt := value.(int64)
+			if !isInt || number < minimum {
+				report.add(position, issueWrongType, "%s: '%s' muss eine ganze Zahl ab %d sein, gefunden: %s", where, key, minimum, describeType(value))
+				continue
+			}
+			settings[key] = int(number)
+		case "exakt":
+			exact, isBool := value.(bool)
+			if !isBool {
+				report.add(position, issueWrongType, "%s: 'exakt' muss true oder false sein, gefunden: %s", where, describeType(value))
+				continue
+			}
+			settings[key] = exact
+		case "zeit": // Dauer als Text wie bei '-zeit 3s'.
+			text, _ := value.(string)
+			budget, err := time.ParseDuration(text)
+			if err != nil || budget < 0 {
+				report.add(position, issueWrongType, "%s: 'zeit' muss eine Dauer in Anführungszeichen sein (z.B. \"3s\"), gefunden: %s", where, describeType(value))
+				continue
+			}
+			settings[key] = budget
+		case "format":
+			format, _ := value.(string)
+			if !isOutputFormat(format) {
+				report.add(position, issueWrongType, "%s: 'format' muss einer dieser Texte sein: %s, gefunden: %s", where, strings.Join(outputFormats, ", "), describeType(value))
+				continue
+			}
+			settings[key] = format
+		case "titel":
+			title, isString := value.(string)
+			if !isString {
+				report.add(position, issueWrongType, "%s: 'titel' muss ein Text sein, gefunden: %s", where, describeType(value))
+				continue
+			}
+			settings[key] = title
+		case "namen": // Eigene Gruppennamen wie bei '-namen "Löwen,Tiger"'.
+			items, isList := value.([]interface{})
+			if !isList {
+				report.add(position, issueWrongType, "%s: 'namen' muss ein Array sein (z.B. [\"Löwen\", \"Tiger\"]), gefunden: %s", where, describeType(value))
+				continue
+			}
+			settings[key] = readNameList(items, "", where+": 'namen'", position, report)
+		}
+	}
+	if settings["groesse"] != nil && settings["gruppen"] != nil {
+		report.add(table.GetPositionPath([]string{"gruppen"}), issueInvalidRule, "%s: 'groesse' und 'gruppen' schließen sich aus, es gilt 'gruppen'", where)
+	}
+	return settings
+}
+
+// applySettings übernimmt die Voreinstellungen für alle Optionen, die beim Aufruf nicht angegeben wurden:
+// 		Die Kommandozeile hat immer Vorrang. Gibt die übernommenen Einstellungen für die Ausgabe zurück.
+func applySettings(options *cliOptions, settings map[string]interface{}) []string {
+	var applied []string
+	sizeGiven := options.SetFlags["groesse"] || options.SetFlags["gruppen"] // Beide legen die Gruppen fest.
+	for _, key := range settingKeys {
+		value, found := settings[key]
+		if !found || options.SetFlags[key] || ((key == "groesse" || key == "gruppen") && sizeGiven) {
+			continue
+		}
+		switch key {
+		case "groesse":
+			options.GroupSize = value.(int)
+		case "gruppen":
+			options.GroupCount = value.(int)
+		case "versuche":
+			options.Attempts = value.(int)
+		case "zeit":
+			options.TimeBudget = value.(time.Duration)
+		case "exakt":
+			options.ExactFirst = value.(bool)
+		case "format":
+			options.Format = value.(string)
+		case "titel":
+			options.Title = value.(string)
+		case "namen":
+			options.GroupNames = value.([]string)
+		}
+		applied = append(applied, fmt.Sprintf("%s = %v", key, value))
+	}
+	return applied
+}
+
+// ############################################################################################
+// migrateConfig stellt den Inhalt einer Datei im alten Format (Version 1) auf das aktuelle Format um:
+// 		Alle Konflikte ohne Abschnitt wandern samt ihrer Kommentare in den Abschnitt [konflikte],
+// 		der vor dem ersten Abschnitt der Datei eingefügt wird, und oben kommt die Versionszeile dazu.
+// Wie beim Befehl 'symmetrisch' werden nur Zeilen verschoben: Kommentare und Formatierung bleiben erhalten.
+// Gibt den neuen Inhalt zurück und ob etwas umgestellt wurde.
+func migrateConfig(content string, tree *toml.Tree) (string, bool, error) {
+	versionLine := -1 // Zeile (ab 0) einer alten Versionsangabe, z.B. 'version = 1'.
+	if value := tree.GetPath([]string{schemaVersionKey}); value != nil {
+		version, isInt := value.(int64)
+		switch {
+		case !isInt:
+			return "", false, fmt.Errorf("'%s' muss eine ganze Zahl sein, gefunden: %s", schemaVersionKey, describeType(value))
+		case version > currentSchemaVersion:
+			return "", false, fmt.Errorf("die Datei hat Version %d, dieses Programm kennt nur Version %d. Bitte verwende eine neuere Version des Klassenmischers", version, currentSchemaVersion)
+		case version == currentSchemaVersion:
+			return content, false, nil
+		}
+		versionLine = tree.GetPositionPath([]string{schemaVersionKey}).Line - 1
+	}
+
+	lines := strings.SplitAfter(content, "\n") // lines[i] ist Zeile i+1 (samt Zeilenumbruch).
+	isBlank := func(i int) bool { return isBlankLine(lines[i]) }
+	isComment := func(i int) bool { return isCommentLine(lines[i]) }
+
+	// Anfangszeilen aller Schlüssel ohne Abschnitt und erste Zeile mit einem Abschnitt.
+	headers := sectionHeaderLines(tree, lines)
+	isSection := make(map[string]bool)
+	firstSection := len(lines)
+	for line, section := range headers {
+		isSection[section] = true
+		if line < firstSection {
+			firstSection = line
+		}
+	}
+	var starts, conflicts []int
+	for _, key := range tree.Keys() {
+		if isSection[key] {
+			continue
+		}
+		position := tree.GetPositionPath([]string{key})
+		if position.Invalid() {
+			position = findKeyPosition(content, "", key) // Z.B. bei einer Inline-Tabelle.
+		}
+		if position.Invalid() || position.Line > len(lines) {
+			continue
+		}
+		line := position.Line - 1
+		starts = append(starts, line)
+		if !isTopLevelKey(key) {
+			conflicts = append(conflicts, line)
+		}
+	}
+	sort.Ints(starts)
+	sort.Ints(conflicts)
+
+	// Zeilen jedes Konflikts bestimmen: vom Kommentar direkt darüber bis vor den nächsten Schlüssel,
+	// 		ohne Leer- und Kommentarzeilen am Ende (die gehören zum nächsten Schlüssel oder Abschnitt).
+	moved := make([]bool, len(lines))
+	for _, start := range conflicts {
+		end := firstSection
+		for _, next := range starts {
+			if next > start && next < end {
+				end = next
+			}
+		}
+		for end-1 > start && (isBlank(end-1) || isComment(end-1)) {
+			end--
+		}
+		begin := start
+		for begin > 0 && isComment(begin-1) && !moved[begin-1] {
+			begin--
+		}
+		for i := begin; i < end; i++ {
+			moved[i] = true
+		}
+	}
+
+	// Der neue Abschnitt kommt vor den ersten Abschnitt, aber vor dessen Kommentar.
+	insertAt := firstSection
+	for insertAt > 0 && insertAt < len(lines) && isComment(insertAt-1) && !moved[insertAt-1] {
+		insertAt--
+	}
+	var block strings.Builder
+	if len(conflicts) > 0 {
+		block.WriteString(fmt.Sprintf("[%s]\n", conflictsSection))
+		for i, line := range lines {
+			if moved[i] {
+				block.WriteString(line)
+				if !strings.HasSuffix(line, "\n") {
+					block.WriteString("\n") // Die letzte Zeile der Datei hat keinen Zeilenumbruch.
+				}
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(schemaVersionLine())
+	if versionLine < 0 && len(lines) > 0 && !isBlank(0) {
+		sb.WriteString("\n")
+	}
+	previousBlank := true // Keine doppelten Leerzeilen dort, wo Konflikte entfernt wurden.
+	removedBefore := false
+	for i, line := range lines {
+		if i == insertAt && block.Len() > 0 {
+			if !previousBlank {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(block.String())
+			sb.WriteString("\n")
+			previousBlank, removedBefore = true, false
+		}
+		if moved[i] || i == versionLine {
+			removedBefore = true
+			continue
+		}
+		if isBlank(i) && previousBlank && removedBefore {
+			continue
+		}
+		sb.WriteString(line)
+		previousBlank, removedBefore = isBlank(i), false
+	}
+	if insertAt == len(lines) && block.Len() > 0 { // Datei ohne Abschnitte: [konflikte] kommt ans Ende.
+		text := sb.String()
+		if !strings.HasSuffix(text, "\n") {
+			sb.WriteString("\n")
+		}
+		if !strings.HasSuffix(text, "\n\n") {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(block.String())
+	}
+
+	// Das Ergebnis muss gültig sein und dieselben Konflikte enthalten.
+	migrated := sb.String()
+	migratedTree, err := toml.Load(migrated)
+	if err != nil {
+		return "", false, fmt.Errorf("die Datei konnte nicht auf Version %d umgestellt werden (%v). Bitte verschiebe die Konflikte von Hand in den Abschnitt [%s]", currentSchemaVersion, err, conflictsSection)
+	}
+	if section, _ := migratedTree.Get(conflictsSection).(*toml.Tree); len(conflicts) > 0 && (section == nil || len(section.Keys()) != len(conflicts)) {
+		return "", false, fmt.Errorf("die Datei konnte nicht auf Version %d umgestellt werden. Bitte verschiebe die Konflikte von Hand in den Abschnitt [%s]", currentSchemaVersion, conflictsSection)
+	}
+	return migrated, true, nil
+}
+
+// ############################################################################################
+// isBlankLine meldet, ob eine Zeile leer ist oder nur Leerzeichen enthält.
+func isBlankLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// isCommentLine meldet, ob eine Zeile nur einen Kommentar enthält.
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+// sectionHeaderLines gibt alle Kopfzeilen von Abschnitten zurück (Zeile ab 0 → Name des Abschnitts ohne Punkt),
+// 		auch von Unterabschnitten wie [import.merkmale] und von jeder Tabelle [[ausgleich]].
+// 'lines' sind die Zeilen der Datei, aus der 'tree' gelesen wurde. Inline-Tabellen haben keine Kopfzeile.
+func sectionHeaderLines(tree *toml.Tree, lines []string) map[int]string {
+	headers := make(map[int]string)
+	var collect func(section string, value interface{})
+	collect = func(section string, value interface{}) {
+		switch table := value.(type) {
+		case *toml.Tree:
+			if line := table.Position().Line - 1; line >= 0 && line < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[line]), "[") {
+				headers[line] = section
+			}
+			for _, key := range table.Keys() {
+				collect(section, table.GetPath([]string{key}))
+			}
+		case []*toml.Tree:
+			for _, element := range table {
+				collect(section, element)
+			}
+		}
+	}
+	for _, key := range tree.Keys() {
+		collect(key, tree.GetPath([]string{key}))
+	}
+	return headers
+}
+
+// sectionTexts gibt den Text aller Abschnitte zurück, die nicht in 'regenerated' stehen (Name → Text),
+// 		jeweils samt der Kommentare direkt über der Kopfzeile und ohne Leerzeilen am Ende.
+// Damit übernimmt der Befehl 'bearbeiten' Abschnitte wie [import] oder [[ausgleich]] unverändert.
+// Steht ein Abschnitt an mehreren Stellen der Datei, werden die Teile mit einer Leerzeile verbunden.
+func sectionTexts(content string, tree *toml.Tree, regenerated []string) map[string]string {
+	lines := strings.SplitAfter(content, "\n")
+	headers := sectionHeaderLines(tree, lines)
+	starts := make([]int, 0, len(headers))
+	for line := range headers {
+		starts = append(starts, line)
+	}
+	sort.Ints(starts)
+
+	// Kommentare direkt über einer Kopfzeile gehören zu diesem Abschnitt.
+	begins := make([]int, len(starts))
+	for i, start := range starts {
+		begins[i] = start
+		for begins[i] > 0 && isCommentLine(lines[begins[i]-1]) && (i == 0 || begins[i]-1 > starts[i-1]) {
+			begins[i]--
+		}
+	}
+
+	skip := make(map[string]bool)
+	for _, section := range regenerated {
+		skip[section] = true
+	}
+	texts := make(map[string]string)
+	for i := 0; i < len(starts); i++ {
+		section, first := headers[starts[i]], i
+		for i+1 < len(starts) && headers[starts[i+1]] == section { // Z.B. [import] und [import.merkmale] direkt nacheinander.
+			i++
+		}
+		if skip[section] {
+			continue
+		}
+		end := len(lines)
+		if i+1 < len(starts) {
+			end = begins[i+1]
+		}
+		for end-1 > starts[i] && isBlankLine(lines[end-1]) {
+			end--
+		}
+		text := strings.Join(lines[begins[first]:end], "")
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n" // Die letzte Zeile der Datei hat keinen Zeilenumbruch.
+		}
+		if previous, found := texts[section]; found {
+			text = previous + "\n" + text
+		}
+		texts[section] = text
+	}
+	return texts
+}
+
+// saveMigratedConfig ersetzt die Datei durch die umgestellte Fassung.
+// Die alte Fassung wird vorher als '.v1.bak' gesichert, weitere als '.v1-2.bak' usw. (siehe writeBackup).
+func saveMigratedConfig(path string, oldData []byte, migrated string) error {
+	backupPath, err := writeBackup(path, ".v1", oldData)
+	if err != nil {
+		return fmt.Errorf("❗️ Warnung: Sicherung '%s' konnte nicht geschrieben werden, die Datei bleibt im alten Format: %w", backupPath, err)
+	}
+	if err := os.WriteFile(path, []byte(migrated), 0644); err != nil {
+		return fmt.Errorf("❗️ Warnung: '%s' konnte nicht umgestellt werden, die Datei bleibt im alten Format: %w", path, err)
+	}
+	fmt.Printf("ℹ️ %s wurde auf das neue Dateiformat (Version %d) umgestellt: Konflikte stehen ab jetzt im Abschnitt [%s].\n",
+		filepath.Base(path), currentSchemaVersion, conflictsSection)
+	fmt.Printf("   Die alte Fassung ist gesichert in %s.\n", backupPath)
+	return nil
+}
@@ -0,0 +1,117 @@
+package main // Tests für das Dateiformat in schema.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"           // Für den Inhalt der alten Fassungen.
+	"os"            // Zum Schreiben und Lesen der Dateien für die Tests.
+	"path/filepath" // Für die Pfade im temporären Ordner.
+	"testing"       // Test-Framework von Go.
+
+	"github.com/pelletier/go-toml" // Zum Parsen der alten Fassung wie beim Laden.
+)
+
+// ############################################################################################
+// TestMigrateConfig prüft die Umstellung vom alten Format (Version 1) auf das aktuelle Format.
+func TestMigrateConfig(t *testing.T) {
+	version := schemaVersionLine()
+	tests := []struct {
+		name        string
+		content     string
+		want        string
+		wantChanged bool
+		wantErr     bool
+	}{
+		{
+			name: "Konflikte samt Kommentaren vor den ersten Abschnitt",
+			content: "schuelerliste = [\"A\", \"B\", \"C\"]\n\n" +
+				"# Konflikte\n\"A\" = [\"B\"] # Streit\n\"B\" = [\"A\"]\n\n" +
+				"# Pflicht\n[zusammen]\n\"A\" = [\"C\"]\n",
+			want: version + "\nschuelerliste = [\"A\", \"B\", \"C\"]\n\n" +
+				"[konflikte]\n# Konflikte\n\"A\" = [\"B\"] # Streit\n\"B\" = [\"A\"]\n\n" +
+				"# Pflicht\n[zusammen]\n\"A\" = [\"C\"]\n",
+			wantChanged: true,
+		},
+		{
+			name:        "alte Versionszeile, ohne Abschnitte",
+			content:     "version = 1\nschuelerliste = [\"A\", \"B\"]\n\"A\" = [\"B\"]\n",
+			want:        version + "schuelerliste = [\"A\", \"B\"]\n\n[konflikte]\n\"A\" = [\"B\"]\n",
+			wantChanged: true,
+		},
+		{
+			name:        "konfliktpaare bleiben ohne Abschnitt",
+			content:     "schuelerliste = [\"A\", \"B\"]\nkonfliktpaare = [[\"A\", \"B\"]]\n\"B\" = [\"A\"]\n",
+			want:        version + "\nschuelerliste = [\"A\", \"B\"]\nkonfliktpaare = [[\"A\", \"B\"]]\n\n[konflikte]\n\"B\" = [\"A\"]\n",
+			wantChanged: true,
+		},
+		{
+			name:        "ohne Konflikte nur die Versionszeile",
+			content:     "schuelerliste = [\"A\"]\n",
+			want:        version + "\nschuelerliste = [\"A\"]\n",
+			wantChanged: true,
+		},
+		{
+			name:        "aktuelle Version bleibt unverändert",
+			content:     version + "[konflikte]\n\"A\" = [\"B\"]\n",
+			want:        version + "[konflikte]\n\"A\" = [\"B\"]\n",
+			wantChanged: false,
+		},
+		{
+			name:    "neuere Version",
+			content: fmt.Sprintf("version = %d\n", currentSchemaVersion+1),
+			wantErr: true,
+		},
+		{
+			name:    "Version ist keine Zahl",
+			content: "version = \"zwei\"\n",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree, err := toml.Load(test.content)
+			if err != nil {
+				t.Fatalf("Testdatei ungültig: %v", err)
+			}
+			got, changed, err := migrateConfig(test.content, tree)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Fehler erwartet, gefunden:\n%s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unerwarteter Fehler: %v", err)
+			}
+			if changed != test.wantChanged {
+				t.Errorf("umgestellt = %v, erwartet %v", changed, test.wantChanged)
+			}
+			if got != test.want {
+				t.Errorf("Ergebnis:\n%s\nerwartet:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+// TestSaveMigratedConfigKeepsBackups prüft, dass eine frühere Sicherung nie überschrieben wird.
+func TestSaveMigratedConfigKeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "klasse.toml")
+	backups := []string{path + ".v1.bak", path + ".v1-2.bak", path + ".v1-3.bak"}
+	for i := range backups {
+		oldData := fmt.Sprintf("\"A\" = [\"B\"] # Fassung %d\n", i+1)
+		if err := saveMigratedConfig(path, []byte(oldData), version2Content); err != nil {
+			t.Fatalf("Umstellung %d: %v", i+1, err)
+		}
+		for j, earlier := range backups[:i+1] {
+			got, err := os.ReadFile(earlier)
+			if want := fmt.Sprintf("\"A\" = [\"B\"] # Fassung %d\n", j+1); err != nil || string(got) != want {
+				t.Errorf("nach Umstellung %d: %s = %q (%v), erwartet %q", i+1, filepath.Base(earlier), got, err, want)
+			}
+		}
+		if got, _ := os.ReadFile(path); string(got) != version2Content {
+			t.Errorf("nach Umstellung %d: Datei = %q, erwartet %q", i+1, got, version2Content)
+		}
+	}
+}
+
+// version2Content ist eine kurze Datei im aktuellen Format.
+var version2Content = schemaVersionLine() + "[konflikte]\n\"A\" = [\"B\"]\n"
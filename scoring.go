@@ -0,0 +1,61 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt" // Zum Formatieren der Zusammenfassung.
+)
+
+// ############################################################################################
+// scoreGrouping bewertet eine ganze Einteilung anhand der weichen Einschränkungen.
+// Für jedes Paar in derselben Gruppe wird sein Gewicht addiert:
+// 		Erfüllte Wünsche ([gerne_zusammen]) zählen positiv, verletzte [lieber_nicht]-Einträge negativ.
+// Ungleich gemischte Gruppen (Ausgleichsregeln mit 'mischen') werden abgezogen, siehe balancePenalty.
+// Ebenso jedes Paar, das schon in den letzten Einteilungen zusammen war (siehe countRepeatedPairs).
+// Je höher die Bewertung, desto besser. Ohne weiche Einschränkungen ist sie immer 0.
+func scoreGrouping(groups [][]string, rules *groupRules) int {
+	score := 0
+	for _, group := range groups {
+		for i, studentA := range group {
+			for _, studentB := range group[i+1:] { // Jedes Paar nur einmal.
+				score += rules.pairWeights[studentA][studentB]
+			}
+		}
+	}
+	return score - rules.balancePenalty(groups) - rules.countRepeatedPairs(groups)
+}
+
+// ############################################################################################
+// isBetterGrouping entscheidet, ob eine neue Einteilung besser ist als die bisher beste.
+// Wichtigstes Kriterium bleibt die Anzahl gruppierter Schüler. Danach zählt, wie nahe die Gruppen
+// 		an der Zielgröße sind (wie bei sizeCombinations), damit z.B. 24 Schüler bei '-groesse 3'
+// 		in 8 Gruppen zu 3 statt in 2er- und 4er-Gruppen eingeteilt werden. Zuletzt zählt die Bewertung.
+func isBetterGrouping(candidate searchResult, best searchResult) bool {
+	if candidate.Grouped != best.Grouped {
+		return candidate.Grouped > best.Grouped
+	}
+	if candidate.Deviation != best.Deviation {
+		return candidate.Deviation < best.Deviation
+	}
+	return candidate.Score > best.Score
+}
+
+// ############################################################################################
+// describeScore fasst die Bewertung einer Einteilung in einem Satz zusammen,
+// 		z.B. "Bewertung: 3 von 4 möglichen (erfüllte Wünsche: 2, verletzte 'lieber nicht': 1, Abzug für Mischung: 0, wiederholte Paare: 0)".
+func describeScore(groups [][]string, rules *groupRules) string {
+	fulfilled, violated := 0, 0
+	for _, group := range groups {
+		for i, studentA := range group {
+			for _, studentB := range group[i+1:] {
+				weight := rules.pairWeights[studentA][studentB]
+				if weight > 0 {
+					fulfilled++
+				} else if weight < 0 {
+					violated++
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("Bewertung: %d von %d möglichen (erfüllte Wünsche: %d, verletzte 'lieber nicht': %d, Abzug für Mischung: %d, wiederholte Paare: %d)",
+		scoreGrouping(groups, rules), rules.maxScore, fulfilled, violated, rules.balancePenalty(groups), rules.countRepeatedPairs(groups))
+}
@@ -0,0 +1,148 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"         // Für die Beschreibung des Seeds.
+	"math"        // Für die größte mögliche Versuchsnummer.
+	"math/rand"   // Jeder Versuch bekommt eine eigene Zufallsquelle.
+	"sync"        // Zum Warten auf alle Worker.
+	"sync/atomic" // Damit ein Worker die anderen bei der bestmöglichen Einteilung stoppen kann.
+	"time"        // Für das Zeitbudget ('-zeit').
+)
+
+// ############################################################################################
+// searchSettings legt fest, wie lange und wie parallel die Zufallssuche läuft.
+type searchSettings struct {
+	Attempts   int           // Anzahl Versuche insgesamt, verteilt auf alle Worker ('-versuche').
+	Workers    int           // Anzahl gleichzeitig suchender Worker ('-worker').
+	TimeBudget time.Duration // Wenn größer als 0: so lange suchen statt einer festen Anzahl Versuche ('-zeit').
+}
+
+// searchResult ist das beste Ergebnis eines Workers.
+type searchResult struct {
+	Groups    [][]string // Die besten gefundenen Gruppen.
+	Ungrouped []string   // Die ungruppierten Schüler dazu.
+	Grouped   int        // Anzahl gruppierter Schüler (-1 = noch kein Ergebnis).
+	Deviation int        // Abweichung der Gruppengrößen von der Zielgröße (siehe groupScenario.deviation).
+	Score     int        // Bewertung der Gruppen (siehe scoreGrouping).
+	Attempt   int        // Nummer des Versuchs, der diese Gruppen gebildet hat (bei Gleichstand gewinnt die kleinere).
+	Attempts  int        // Anzahl durchgeführter Versuche.
+}
+
+// ############################################################################################
+// findBestRandomGrouping wiederholt die zufällige Gruppenbildung und gibt das beste Ergebnis zurück
+// 		(siehe isBetterGrouping): zuerst möglichst viele gruppierte Schüler, dann Gruppen möglichst
+// 		nahe an der Zielgröße, dann die beste Bewertung. Bei Gleichstand gewinnt der frühere Versuch.
+// Die Versuche werden auf mehrere Worker verteilt, die gleichzeitig auf allen CPU-Kernen suchen.
+// Jeder Versuch hat eine eigene Zufallsquelle, deren Startwert nur vom Seed und seiner Nummer abhängt
+// 		(siehe attemptSeed). Mit fester Anzahl Versuche ist das Ergebnis daher für denselben Seed
+// 		immer gleich, egal wie viele Worker suchen.
+// Mit Zeitbudget suchen alle Worker bis zum Ablauf der Zeit (oder bis zur bestmöglichen Einteilung).
+// Zurückgegeben wird auch die Anzahl tatsächlich durchgeführter Versuche aller Worker.
+func findBestRandomGrouping(config *Config, rules *groupRules, scenario groupScenario, search searchSettings, rng *rand.Rand) ([][]string, []string, int) {
+	workers := search.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if search.TimeBudget <= 0 && workers > search.Attempts { // Nicht mehr Worker als Versuche.
+		workers = search.Attempts
+	}
+	var deadline time.Time // Bleibt leer, wenn eine feste Anzahl Versuche gilt.
+	if search.TimeBudget > 0 {
+		deadline = time.Now().Add(search.TimeBudget)
+	}
+	baseSeed := rng.Int63() // Startwert aller Versuche dieses Szenarios.
+
+	// Kleinste Nummer eines Versuchs, der die bestmögliche Einteilung gefunden hat.
+	// Spätere Versuche können ihn nicht mehr schlagen und werden übersprungen.
+	var optimalAttempt atomic.Int64
+	optimalAttempt.Store(math.MaxInt64)
+	results := make([]searchResult, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			results[w] = searchWorker(config, rules, scenario, w, workers, search.Attempts, deadline, baseSeed, &optimalAttempt)
+		}(w)
+	}
+	wg.Wait()
+
+	// Ergebnisse zusammenführen, bei Gleichstand gewinnt der Versuch mit der kleineren Nummer.
+	best := searchResult{Groups: [][]string{}, Ungrouped: []string{}, Grouped: -1}
+	totalAttempts := 0
+	for _, result := range results {
+		totalAttempts += result.Attempts
+		if isBetterGrouping(result, best) || !isBetterGrouping(best, result) && result.Attempt < best.Attempt {
+			best = result
+		}
+	}
+	return best.Groups, best.Ungrouped, totalAttempts
+}
+
+// ############################################################################################
+// searchWorker führt die Versuche mit den Nummern 'first', 'first+step', 'first+2*step', ... durch
+// 		(bis 'attempts' bzw. bis 'deadline', wenn gesetzt) und gibt das beste Ergebnis zurück.
+// Findet ein Versuch die bestmögliche Einteilung, merkt er sich seine Nummer in 'optimalAttempt':
+// 		Alle Worker überspringen dann spätere Versuche, mit Zeitbudget hören alle auf.
+func searchWorker(config *Config, rules *groupRules, scenario groupScenario, first int, step int, attempts int,
+	deadline time.Time, baseSeed int64, optimalAttempt *atomic.Int64) searchResult {
+	best := searchResult{Groups: [][]string{}, Ungrouped: []string{}, Grouped: -1}
+	timed := !deadline.IsZero()
+	done := 0 // Anzahl durchgeführter Versuche.
+	// Näher an der Zielgröße geht es nicht: Wird das erreicht, muss nicht weiter gesucht werden.
+	minimalDeviation := scenario.minimalDeviation(len(config.Schuelerliste))
+
+	for attempt := first; ; attempt += step { // Wiederholt den Gruppierungsprozess mehrmals.
+		if timed && (done > 0 && time.Now().After(deadline) || optimalAttempt.Load() != math.MaxInt64) {
+			break // Mindestens ein Versuch, auch wenn die Zeit sehr knapp ist.
+		}
+		if !timed && (attempt >= attempts || int64(attempt) > optimalAttempt.Load()) {
+			break
+		}
+
+		rng := rand.New(rand.NewSource(attemptSeed(baseSeed, attempt)))
+		currentGroups, currentUngrouped := scenario.form(config.Schuelerliste, rules, rng)
+		done++
+		current := searchResult{
+			Groups:    currentGroups,
+			Ungrouped: currentUngrouped,
+			Grouped:   len(config.Schuelerliste) - len(currentUngrouped), // Anzahl der gruppierten Schüler in diesem Versuch.
+			Deviation: scenario.deviation(currentGroups),
+			Score:     scoreGrouping(currentGroups, rules),
+			Attempt:   attempt,
+		}
+
+		if isBetterGrouping(current, best) { // Wenn dieser Versuch besser war.
+			best = current
+			// Alle Schüler gruppiert, bestmögliche Gruppengrößen und Bewertung: besser geht es nicht, Abbruch.
+			if len(currentUngrouped) == 0 && current.Deviation <= minimalDeviation && current.Score >= rules.maxScore {
+				for found := optimalAttempt.Load(); int64(attempt) < found && !optimalAttempt.CompareAndSwap(found, int64(attempt)); {
+					found = optimalAttempt.Load() // Ein anderer Worker war gleichzeitig erfolgreich.
+				}
+				break
+			}
+		}
+	}
+	best.Attempts = done
+	return best
+}
+
+// attemptSeed gibt den Startwert der Zufallsquelle für den Versuch mit der Nummer 'attempt' zurück.
+// Er hängt nur vom Seed des Szenarios und der Nummer ab, nicht davon, welcher Worker den Versuch macht.
+func attemptSeed(baseSeed int64, attempt int) int64 {
+	return baseSeed ^ int64(uint64(attempt+1)*0x9E3779B97F4A7C15) // Goldener Schnitt: gut gestreute Startwerte.
+}
+
+// ############################################################################################
+// describeSeed erklärt, wie ein Ergebnis mit seinem Seed wiederholt werden kann.
+// Die Anzahl Worker spielt dafür keine Rolle, nur eine geänderte Anzahl Versuche muss mit angegeben werden.
+func describeSeed(seed int64, search searchSettings) string {
+	if search.TimeBudget > 0 { // Wie viele Versuche in die Zeit passen, hängt vom Rechner ab.
+		return fmt.Sprintf("Seed: %d (mit '-zeit' nicht genau wiederholbar, dafür '-versuche' verwenden)", seed)
+	}
+	if search.Attempts != defaultAttempts {
+		return fmt.Sprintf("Seed: %d (wiederholbar mit '-seed %d -versuche %d')", seed, seed, search.Attempts)
+	}
+	return fmt.Sprintf("Seed: %d (wiederholbar mit '-seed %d')", seed, seed)
+}
@@ -0,0 +1,355 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"          // Für die Meldungen und die neuen Einträge.
+	"os"           // Zum Lesen und Schreiben der Konfigurationsdatei.
+	"sort"         // Für eine stabile Reihenfolge der neuen Einträge.
+	"strings"      // Zum Zusammensetzen der Einträge.
+	"unicode/utf8" // Spaltenangaben von go-toml zählen Zeichen, nicht Bytes.
+
+	"github.com/pelletier/go-toml" // Zum Finden der Konflikte in der Datei.
+)
+
+// ############################################################################################
+// conflictPairsKey ist der Schlüssel für Konflikte, die nur einmal angegeben werden, z.B.:
+// 		konfliktpaare = [["Anna", "Ben"], ["Carla", "Dario", "Emil"]]
+// Jede Liste enthält Schüler, die sich alle gegenseitig ausschließen (in beide Richtungen).
+// Wie alle Schlüssel ohne Abschnitt muss er vor dem ersten Abschnitt stehen.
+const conflictPairsKey = "konfliktpaare"
+
+// ############################################################################################
+// readConflictPairs liest 'konfliktpaare' und trägt jeden Konflikt in beide Richtungen in 'constraints' ein.
+// Bereits vorhandene Einträge werden nicht verdoppelt. Probleme kommen in den Prüfbericht (nil = keiner).
+// Gibt die gültigen Gruppen zurück (z.B. damit 'bearbeiten' sie wieder als Gruppen schreiben kann).
+func readConflictPairs(tree *toml.Tree, constraints map[string][]string, report *validationReport) [][]string {
+	val := tree.GetPath([]string{conflictPairsKey})
+	if val == nil {
+		return nil // Schlüssel ist optional.
+	}
+	position := tree.GetPositionPath([]string{conflictPairsKey})
+	pairs, ok := val.([]interface{})
+	if !ok {
+		report.add(position, issueWrongType, "'%s' muss eine Liste von Listen sein, z.B. [[\"Anna\", \"Ben\"]], gefunden: %s", conflictPairsKey, describeType(val))
+		return nil
+	}
+	var groups [][]string
+	for i, pair := range pairs {
+		where := fmt.Sprintf("Eintrag %d in '%s'", i+1, conflictPairsKey)
+		items, isList := pair.([]interface{})
+		if !isList {
+			report.add(position, issueWrongType, "%s muss eine Liste sein (z.B. [\"Anna\", \"Ben\"]), gefunden: %s", where, describeType(pair))
+			continue
+		}
+		students := readNameList(items, "", where, position, report)
+		if len(students) < 2 {
+			report.add(position, issueInvalidRule, "%s braucht mindestens zwei verschiedene Namen und wird ignoriert", where)
+			continue
+		}
+		groups = append(groups, students)
+		for _, a := range students {
+			for _, b := range students {
+				if a != b && !containsString(constraints[a], b) {
+					constraints[a] = append(constraints[a], b)
+				}
+			}
+		}
+	}
+	return groups
+}
+
+// formatConflictPairs schreibt die Gruppen aus 'konfliktpaare' als TOML-Array, z.B. [["Anna", "Ben"]].
+func formatConflictPairs(groups [][]string) string {
+	items := make([]string, len(groups))
+	for i, group := range groups {
+		items[i] = formatStringSliceToTomlArray(group)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// containsString prüft, ob 'name' in der Liste vorkommt.
+func containsString(list []string, name string) bool {
+	for _, item := range list {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ############################################################################################
+// runSymmetrizeCommand ergänzt für den Befehl 'symmetrisch' alle fehlenden Gegenrichtungen der Konflikte
+// 		direkt in der Konfigurationsdatei. Steht "A" = ["B"] in der Datei, wird "A" bei "B" ergänzt.
+// Die Datei wird nicht neu geschrieben, sondern nur an diesen Stellen ergänzt: Kommentare,
+// 		Reihenfolge und Formatierung bleiben erhalten. Vorher wird eine Sicherung angelegt (siehe writeBackup).
+// Gibt den Namen der Sicherung zurück (leer, wenn die Datei unverändert bleibt).
+func runSymmetrizeCommand(config *Config) (string, error) {
+	data, err := os.ReadFile(config.Path)
+	if err != nil {
+		return "", fmt.Errorf("❌ Fehler beim Lesen der Datei %s: %w", config.Path, err)
+	}
+	content := string(data)
+	tree, err := toml.Load(content)
+	if err != nil {
+		return "", fmt.Errorf("❌ Fehler beim Parsen der TOML-Datei: %w", err)
+	}
+
+	if version, _ := tree.GetPath([]string{schemaVersionKey}).(int64); version != currentSchemaVersion {
+		return "", fmt.Errorf("❌ %s ist noch im alten Dateiformat und konnte nicht umgestellt werden. Bitte zuerst die Konflikte in den Abschnitt [%s] verschieben", config.Path, conflictsSection)
+	}
+
+	// Nur die Konflikte im Abschnitt [konflikte], ohne 'konfliktpaare' (die sind schon symmetrisch).
+	conflicts, _ := tree.Get(conflictsSection).(*toml.Tree)
+	declared := make(map[string][]string)
+	var keys []string // Schlüssel der Konflikte (für die Stelle neuer Einträge).
+	for _, key := range keysOf(conflicts) {
+		list, isList := conflicts.GetPath([]string{key}).([]interface{})
+		if !isList {
+			continue // Fehlerhafte Einträge werden beim Laden bereits gemeldet.
+		}
+		names := []string{} // Auch ein leeres Array zählt als eigene Zeile.
+		for _, item := range list {
+			if name, isString := item.(string); isString {
+				names = append(names, name)
+			}
+		}
+		declared[key] = names
+		keys = append(keys, key)
+	}
+	fromPairs := make(map[string][]string)
+	readConflictPairs(tree, fromPairs, nil) // Probleme meldet bereits das Laden der Konfiguration.
+
+	// Fehlende Gegenrichtungen sammeln.
+	missing := make(map[string][]string)
+	for _, student := range sortedKeys(declared) {
+		for _, other := range declared[student] {
+			if other == student || containsString(declared[other], student) || containsString(fromPairs[other], student) || containsString(missing[other], student) {
+				continue
+			}
+			missing[other] = append(missing[other], student)
+		}
+	}
+	if len(missing) == 0 {
+		fmt.Println("✅ Alle Konflikte sind bereits symmetrisch, die Datei bleibt unverändert.")
+		return "", nil
+	}
+
+	// Änderungen als Einfügungen in den Text sammeln und von hinten nach vorne anwenden.
+	type insertion struct {
+		Offset int    // Byte-Position in der Datei.
+		Text   string // Einzufügender Text.
+	}
+	var insertions []insertion
+	var newKeys []string // Schüler, die noch keine eigene Zeile haben.
+	for _, student := range sortedKeys(missing) {
+		if _, exists := declared[student]; !exists {
+			newKeys = append(newKeys, student)
+			continue
+		}
+		position := conflicts.GetPositionPath([]string{student})
+		offset, err := arrayEndOffset(content, position.Line, position.Col)
+		if err != nil {
+			return "", fmt.Errorf("❌ Konflikte von '%s' konnten nicht ergänzt werden: %w", student, err)
+		}
+		insertions = append(insertions, insertion{Offset: offset, Text: joinArrayItems(content, offset, missing[student])})
+	}
+	if len(newKeys) > 0 {
+		var sb strings.Builder
+		for _, student := range newKeys {
+			sb.WriteString(fmt.Sprintf("%q = %s\n", student, formatStringSliceToTomlArray(missing[student])))
+		}
+		offset := newEntriesOffset(content, conflicts, keys) // Es gibt Konflikte, also auch [konflikte].
+		text := sb.String()
+		if offset == len(content) && content != "" && !strings.HasSuffix(content, "\n") {
+			text = "\n" + text // Die letzte Zeile der Datei hat keinen Zeilenumbruch.
+		}
+		insertions = append(insertions, insertion{Offset: offset, Text: text})
+	}
+	sort.SliceStable(insertions, func(i, j int) bool {
+		return insertions[i].Offset > insertions[j].Offset
+	})
+	for _, ins := range insertions {
+		content = content[:ins.Offset] + ins.Text + content[ins.Offset:]
+	}
+
+	// Das Ergebnis muss wieder gültig sein, bevor die Datei ersetzt wird.
+	if _, err := toml.Load(content); err != nil {
+		return "", fmt.Errorf("❌ Die ergänzte Datei wäre ungültig, '%s' bleibt unverändert: %w", config.Path, err)
+	}
+	backupPath, err := writeBackup(config.Path, "", data)
+	if err != nil {
+		return "", fmt.Errorf("❌ Fehler beim Schreiben der Sicherung '%s': %w", backupPath, err)
+	}
+	if err := os.WriteFile(config.Path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("❌ Fehler beim Schreiben der Datei '%s': %w", config.Path, err)
+	}
+
+	for _, student := range sortedKeys(missing) {
+		fmt.Printf("✅ '%s' ergänzt um %s\n", student, formatNameList(missing[student]))
+	}
+	fmt.Printf("✅ %s ist jetzt symmetrisch (Sicherung: %s).\n", config.Path, backupPath)
+	return backupPath, nil
+}
+
+// writeBackup sichert 'data' neben der Datei 'path', ohne eine frühere Sicherung zu überschreiben:
+// 		zuerst 'path' + tag + '.bak', dann '-2.bak', '-3.bak' usw. statt '.bak'. Gibt den Namen der Sicherung zurück.
+func writeBackup(path, tag string, data []byte) (string, error) {
+	for n := 1; ; n++ {
+		backupPath := path + tag + ".bak"
+		if n > 1 {
+			backupPath = fmt.Sprintf("%s%s-%d.bak", path, tag, n)
+		}
+		// O_EXCL, damit auch eine gleichzeitig entstandene Sicherung nicht überschrieben wird.
+		backup, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err == nil {
+			_, err = backup.Write(data)
+			if closeErr := backup.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return backupPath, err
+	}
+}
+
+// ############################################################################################
+// lineOffset gibt die Byte-Position zurück, an der die Zeile 'line' (ab 1) beginnt.
+func lineOffset(content string, line int) int {
+	offset := 0
+	for current := 1; current < line; current++ {
+		next := strings.IndexByte(content[offset:], '\n')
+		if next < 0 {
+			return len(content)
+		}
+		offset += next + 1
+	}
+	return offset
+}
+
+// arrayEndOffset sucht ab dem Schlüssel an Zeile 'line' und Spalte 'col' (Zeichen, ab 1)
+// 		das Array des Werts und gibt die Position direkt nach seinem letzten Element zurück.
+// Zeichenketten und Kommentare werden dabei übersprungen, damit auch mehrzeilige Arrays
+// 		mit Kommentaren richtig ergänzt werden.
+func arrayEndOffset(content string, line int, col int) (int, error) {
+	offset := lineOffset(content, line)
+	for i := 1; i < col && offset < len(content); i++ { // Spalte in Bytes umrechnen.
+		_, size := utf8.DecodeRuneInString(content[offset:])
+		offset += size
+	}
+
+	depth := 0          // Verschachtelungstiefe der Arrays.
+	seenEquals := false // Das '=' zwischen Schlüssel und Wert wurde gefunden.
+	lastItemEnd := -1   // Position nach dem letzten Zeichen, das kein Leerraum oder Kommentar ist.
+	for i := offset; i < len(content); i++ {
+		switch c := content[i]; {
+		case c == '"' || c == '\'': // Zeichenkette (auch ein Schlüssel in Anführungszeichen) überspringen.
+			end := stringEnd(content, i)
+			if end < 0 {
+				return 0, fmt.Errorf("Zeichenkette ohne Ende in Zeile %d", line)
+			}
+			i = end
+			lastItemEnd = end + 1
+		case c == '#' && seenEquals: // Kommentar bis zum Zeilenende überspringen.
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case c == '=' && !seenEquals:
+			seenEquals = true
+		case c == '[' && seenEquals:
+			depth++
+			lastItemEnd = i + 1
+		case c == ']' && seenEquals:
+			depth--
+			if depth == 0 {
+				return lastItemEnd, nil
+			}
+			lastItemEnd = i + 1
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		default:
+			if seenEquals && depth == 0 {
+				return 0, fmt.Errorf("der Wert in Zeile %d ist kein Array", line)
+			}
+			lastItemEnd = i + 1
+		}
+	}
+	return 0, fmt.Errorf("Array ohne ']' ab Zeile %d", line)
+}
+
+// stringEnd gibt die Position des schließenden Anführungszeichens der Zeichenkette ab 'start' zurück.
+func stringEnd(content string, start int) int {
+	quote := content[start]
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			if quote == '"' {
+				i++ // Escape-Sequenz überspringen.
+			}
+		case quote:
+			return i
+		case '\n':
+			return -1
+		}
+	}
+	return -1
+}
+
+// joinArrayItems formatiert die Namen so, dass sie an 'offset' ins Array passen (mit oder ohne führendes Komma).
+func joinArrayItems(content string, offset int, names []string) string {
+	items := strings.TrimSuffix(strings.TrimPrefix(formatStringSliceToTomlArray(names), "["), "]")
+	switch content[offset-1] {
+	case '[':
+		return items // Leeres Array.
+	case ',':
+		return " " + items // Das Array endet bereits mit einem Komma.
+	default:
+		return ", " + items
+	}
+}
+
+// newEntriesOffset bestimmt, wo neue Konflikt-Zeilen im Abschnitt [konflikte] eingefügt werden:
+// 		nach dem letzten Konflikt, sonst direkt nach der Kopfzeile des Abschnitts.
+func newEntriesOffset(content string, conflicts *toml.Tree, conflictKeys []string) int {
+	if len(conflictKeys) > 0 {
+		anchor := conflictKeys[0]
+		for _, key := range conflictKeys[1:] {
+			if conflicts.GetPositionPath([]string{key}).Line > conflicts.GetPositionPath([]string{anchor}).Line {
+				anchor = key
+			}
+		}
+		position := conflicts.GetPositionPath([]string{anchor})
+		if end, err := arrayEndOffset(content, position.Line, position.Col); err == nil {
+			end += closingBracketOffset(content[end:]) // Mehrzeilige Arrays enden erst mit ']'.
+			if newline := strings.IndexByte(content[end:], '\n'); newline >= 0 {
+				return end + newline + 1 // Nach dem Zeilenende (samt Kommentar) der letzten Zeile.
+			}
+			return len(content)
+		}
+	}
+	return lineOffset(content, conflicts.Position().Line+1) // Nach der Zeile [konflikte].
+}
+
+// closingBracketOffset gibt die Position direkt nach dem schließenden ']' zurück,
+// 		wobei Leerraum und Kommentare davor übersprungen werden.
+func closingBracketOffset(rest string) int {
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case ']':
+			return i + 1
+		case '#':
+			for i < len(rest) && rest[i] != '\n' {
+				i++
+			}
+		}
+	}
+	return 0
+}
+
+// keysOf gibt die Schlüssel eines Abschnitts zurück (keine, wenn es ihn nicht gibt).
+func keysOf(section *toml.Tree) []string {
+	if section == nil {
+		return nil
+	}
+	return section.Keys()
+}
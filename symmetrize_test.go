@@ -0,0 +1,137 @@
+package main // Tests für den Befehl 'symmetrisch' in symmetrize.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"os"            // Zum Schreiben und Lesen der Konfigurationsdateien für die Tests.
+	"path/filepath" // Für die Pfade im temporären Ordner.
+	"testing"       // Test-Framework von Go.
+)
+
+// ############################################################################################
+// TestArrayEndOffset prüft, dass die Position direkt nach dem letzten Element des Arrays gefunden wird.
+// 'want' ist der Text der Datei bis zu dieser Position.
+func TestArrayEndOffset(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		line, col int
+		want      string
+		wantErr   bool
+	}{
+		{"einzeilig", `"Anna" = ["Ben", "Carla"] # Kommentar`, 1, 1, `"Anna" = ["Ben", "Carla"`, false},
+		{"leer", `"Anna" = []`, 1, 1, `"Anna" = [`, false},
+		{"Klammer in Zeichenkette", `"Anna" = ["Ben]", 'Carla[']`, 1, 1, `"Anna" = ["Ben]", 'Carla['`, false},
+		{"eingerückt", "[konflikte]\n  \"Anna\" = [\"Ben\"]\n", 2, 3, "[konflikte]\n  \"Anna\" = [\"Ben\"", false},
+		{"Umlaut vor dem Schlüssel", "x = 1\n\"Jürgen\" = [\"Ben\"]\n\"Ömer\" = [\"Anna\"]", 3, 1, "x = 1\n\"Jürgen\" = [\"Ben\"]\n\"Ömer\" = [\"Anna\"", false},
+		{
+			"mehrzeilig mit Kommentaren und Komma",
+			"\"Anna\" = [\n  \"Ben\", # seit Mai ]\n  \"Carla\", # [alt]\n]\n",
+			1, 1,
+			"\"Anna\" = [\n  \"Ben\", # seit Mai ]\n  \"Carla\",",
+			false,
+		},
+		{"kein Array", `"Anna" = 3`, 1, 1, "", true},
+		{"ohne Ende", "\"Anna\" = [\"Ben\",\n", 1, 1, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := arrayEndOffset(test.content, test.line, test.col)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Fehler erwartet, gefunden: Position %d", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unerwarteter Fehler: %v", err)
+			}
+			if test.content[:got] != test.want {
+				t.Errorf("Text bis zur Position = %q, erwartet %q", test.content[:got], test.want)
+			}
+		})
+	}
+}
+
+// ############################################################################################
+// TestRunSymmetrizeCommand prüft, dass fehlende Gegenrichtungen an der richtigen Stelle ergänzt werden.
+// Der Rest der Datei und eine frühere Sicherung müssen dabei unverändert bleiben.
+func TestRunSymmetrizeCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string // Gleich 'content', wenn nichts zu ergänzen ist.
+	}{
+		{
+			name:    "ergänzen und neue Zeile",
+			content: "version = 2\n\n[konflikte]\n# Streit\n\"Anna\" = [\"Ben\", \"Carla\"] # seit Mai\n\"Ben\" = []\n\n[zusammen]\n",
+			want:    "version = 2\n\n[konflikte]\n# Streit\n\"Anna\" = [\"Ben\", \"Carla\"] # seit Mai\n\"Ben\" = [\"Anna\"]\n\"Carla\" = [\"Anna\"]\n\n[zusammen]\n",
+		},
+		{
+			name:    "mehrzeiliges Array",
+			content: "version = 2\n[konflikte]\n\"Ben\" = [\n  \"Anna\", # seit Mai\n]\n\"Anna\" = [\"Carla\"]",
+			want:    "version = 2\n[konflikte]\n\"Ben\" = [\n  \"Anna\", # seit Mai\n]\n\"Anna\" = [\"Carla\", \"Ben\"]\n\"Carla\" = [\"Anna\"]\n",
+		},
+		{
+			name:    "Gegenrichtung steht in konfliktpaare",
+			content: "version = 2\nkonfliktpaare = [[\"Ben\", \"Anna\"]]\n[konflikte]\n\"Anna\" = [\"Ben\"]\n",
+			want:    "version = 2\nkonfliktpaare = [[\"Ben\", \"Anna\"]]\n[konflikte]\n\"Anna\" = [\"Ben\"]\n",
+		},
+		{
+			name:    "bereits symmetrisch",
+			content: "version = 2\n[konflikte]\n\"Anna\" = [\"Ben\"]\n\"Ben\" = [\"Anna\"]\n",
+			want:    "version = 2\n[konflikte]\n\"Anna\" = [\"Ben\"]\n\"Ben\" = [\"Anna\"]\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "klasse.toml")
+			if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path+".bak", []byte("# frühere Sicherung\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			backupPath, err := runSymmetrizeCommand(&Config{Path: path})
+			if err != nil {
+				t.Fatalf("unerwarteter Fehler: %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("Datei nach 'symmetrisch':\n%s\nerwartet:\n%s", got, test.want)
+			}
+			if earlier, _ := os.ReadFile(path + ".bak"); string(earlier) != "# frühere Sicherung\n" {
+				t.Errorf("frühere Sicherung wurde überschrieben: %q", earlier)
+			}
+			if changed := test.content != test.want; !changed {
+				if backupPath != "" {
+					t.Errorf("Sicherung %s angelegt, obwohl nichts zu ergänzen war", backupPath)
+				}
+				return
+			}
+			if want := path + "-2.bak"; backupPath != want {
+				t.Errorf("Sicherung = %s, erwartet %s", backupPath, want)
+			}
+			if backup, _ := os.ReadFile(backupPath); string(backup) != test.content {
+				t.Errorf("Sicherung = %q, erwartet %q", backup, test.content)
+			}
+		})
+	}
+}
+
+// TestRunSymmetrizeCommandOldFormat prüft, dass Dateien im alten Format nicht geändert werden.
+func TestRunSymmetrizeCommandOldFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "klasse.toml")
+	content := "\"Anna\" = [\"Ben\"]\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runSymmetrizeCommand(&Config{Path: path}); err == nil {
+		t.Error("Fehler erwartet, weil die Datei im alten Format ist")
+	}
+	if got, _ := os.ReadFile(path); string(got) != content {
+		t.Errorf("Datei wurde geändert: %q", got)
+	}
+}
@@ -0,0 +1,198 @@
+package main // Gehört zum selben ausführbaren Programm wie main.go.
+
+// ############################################################################################
+import ( // Importiert notwendige Pakete.
+	"fmt"           // Zum Formatieren der Meldungen.
+	"path/filepath" // Für den Dateinamen im Bericht.
+	"regexp"        // Zum Lesen der Fundstelle aus Fehlermeldungen von go-toml.
+	"sort"          // Damit der Bericht der Reihenfolge der Datei folgt.
+	"strconv"       // Zum Umwandeln von Zeile und Spalte.
+	"strings"       // Zum Zusammensetzen des Berichts.
+	"time"          // Für die Beschreibung von Datumswerten.
+
+	"github.com/pelletier/go-toml" // Für die Fundstellen in der Datei.
+)
+
+// ############################################################################################
+// Arten von Problemen in der Konfigurationsdatei (für den Prüfbericht).
+const (
+	issueSyntax       = "Syntaxfehler"          // Die Datei ist kein gültiges TOML.
+	issueWrongType    = "Falscher Typ"          // Z.B. ein Text statt eines Arrays.
+	issueNotString    = "Kein Name"             // Ein Eintrag in einer Namensliste ist kein Text.
+	issueEmptyName    = "Leerer Name"           // Ein Name ist leer ("").
+	issueSelfConflict = "Eigener Name"          // Ein Schüler steht in seiner eigenen Liste.
+	issueUnknownKey   = "Unbekannter Schlüssel" // Ein Schlüssel oder Abschnitt, den das Programm nicht kennt.
+	issueDuplicate    = "Doppelt"               // Ein Name steht mehrfach in derselben Liste.
+	issueUnknownName  = "Unbekannter Name"      // Ein Name steht nicht in der Schülerliste.
+	issueInvalidRule  = "Unvollständige Regel"  // Eine Regel fehlt eine Angabe und wird ignoriert.
+)
+
+// ############################################################################################
+// configIssue ist ein Problem in der Konfigurationsdatei mit seiner Fundstelle.
+type configIssue struct {
+	Position toml.Position // Zeile und Spalte in der Datei (ungültig, wenn unbekannt).
+	Kind     string        // Art des Problems (z.B. issueWrongType).
+	Message  string        // Beschreibung des Problems.
+}
+
+// validationReport sammelt alle Probleme beim Lesen der Konfigurationsdatei,
+// 		damit sie vor der Gruppierung gemeinsam ausgegeben werden können (statt einzeln beim Lesen).
+type validationReport struct {
+	Path      string                   // Pfad der Konfigurationsdatei.
+	Issues    []configIssue            // Alle gefundenen Probleme.
+	Positions map[string]toml.Position // Fundstellen aller Schlüssel (siehe positionKey).
+}
+
+// newValidationReport erstellt einen leeren Bericht für die Datei 'path'.
+func newValidationReport(path string) *validationReport {
+	return &validationReport{Path: path, Positions: make(map[string]toml.Position)}
+}
+
+// add fügt ein Problem hinzu. Ohne Bericht (nil, z.B. beim Befehl 'symmetrisch') passiert nichts.
+func (r *validationReport) add(position toml.Position, kind string, format string, args ...interface{}) {
+	if r == nil {
+		return
+	}
+	r.Issues = append(r.Issues, configIssue{Position: position, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// position gibt die Fundstelle eines Schlüssels im Abschnitt zurück ("" = ohne Abschnitt).
+// Konflikte aus 'konfliktpaare' haben keine eigene Zeile, dann zählt die Zeile von 'konfliktpaare'.
+func (r *validationReport) position(section string, key string) toml.Position {
+	if r == nil {
+		return toml.Position{}
+	}
+	position, found := r.Positions[positionKey(section, key)]
+	if !found && section == conflictsSection {
+		position = r.Positions[positionKey("", conflictPairsKey)]
+	}
+	return position
+}
+
+// lines gibt alle Probleme in der Reihenfolge der Datei als Text zurück,
+// 		z.B. "Zeile 4, Spalte 1 (Falscher Typ): ...". Probleme ohne Fundstelle stehen am Ende.
+func (r *validationReport) lines() []string {
+	issues := append([]configIssue{}, r.Issues...)
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i].Position, issues[j].Position
+		if a.Invalid() || b.Invalid() {
+			return !a.Invalid() && b.Invalid()
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		if issue.Position.Invalid() {
+			lines[i] = fmt.Sprintf("(%s): %s", issue.Kind, issue.Message)
+		} else {
+			lines[i] = fmt.Sprintf("Zeile %d, Spalte %d (%s): %s", issue.Position.Line, issue.Position.Col, issue.Kind, issue.Message)
+		}
+	}
+	return lines
+}
+
+// print gibt den Bericht in einem Stück aus.
+func (r *validationReport) print() {
+	if len(r.Issues) == 0 {
+		fmt.Printf("✅ Keine Probleme in %s gefunden.\n", filepath.Base(r.Path))
+		return
+	}
+	fmt.Printf("❗️ Warnung: %d Probleme in %s gefunden:\n", len(r.Issues), filepath.Base(r.Path))
+	for _, line := range r.lines() {
+		fmt.Printf("   %s\n", line)
+	}
+	fmt.Println("Fehlerhafte Einträge werden ignoriert. Die Gruppierung wird fortgesetzt, aber es wird empfohlen, die Datei zu korrigieren.")
+}
+
+// ############################################################################################
+// syntaxErrorPattern erkennt die Fundstelle in Fehlermeldungen von go-toml, z.B. "(3, 1): unterminated array".
+var syntaxErrorPattern = regexp.MustCompile(`^\((\d+), (\d+)\): (.*)$`)
+
+// syntaxError beschreibt einen Parserfehler von go-toml mit Zeile und Spalte.
+func syntaxError(path string, err error) error {
+	message := err.Error()
+	if match := syntaxErrorPattern.FindStringSubmatch(message); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		col, _ := strconv.Atoi(match[2])
+		return fmt.Errorf("❌ Fehler beim Parsen der TOML-Datei %s, Zeile %d, Spalte %d (%s): %s", filepath.Base(path), line, col, issueSyntax, match[3])
+	}
+	return fmt.Errorf("❌ Fehler beim Parsen der TOML-Datei %s: %w", filepath.Base(path), err)
+}
+
+// ############################################################################################
+// describeType gibt den TOML-Typ eines Werts verständlich zurück (statt Go-Typen wie '*toml.Tree').
+func describeType(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("Text %q", v)
+	case int64:
+		return fmt.Sprintf("Zahl %d", v)
+	case float64:
+		return fmt.Sprintf("Kommazahl %v", v)
+	case bool:
+		return fmt.Sprintf("Wahrheitswert %v", v)
+	case time.Time, toml.LocalDate, toml.LocalDateTime, toml.LocalTime:
+		return "Datum/Uhrzeit"
+	case []interface{}:
+		return "Array"
+	case *toml.Tree:
+		return "Tabelle"
+	case []*toml.Tree:
+		return "Liste von Tabellen"
+	case nil:
+		return "nichts"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// readNameList liest ein Array von Namen (z.B. die Konflikte eines Schülers) und meldet dabei
+// 		Nicht-Text-Einträge, leere Namen, den eigenen Namen und doppelte Namen.
+// 'owner' ist der Schüler, dem die Liste gehört ("" = keiner), 'where' beschreibt die Liste für die Meldungen.
+func readNameList(items []interface{}, owner string, where string, position toml.Position, report *validationReport) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i, item := range items {
+		name, isString := item.(string)
+		switch {
+		case !isString:
+			report.add(position, issueNotString, "%s: Eintrag %d muss ein Name in Anführungszeichen sein, gefunden: %s", where, i+1, describeType(item))
+		case strings.TrimSpace(name) == "":
+			report.add(position, issueEmptyName, "%s: Eintrag %d ist leer", where, i+1)
+		case name == owner:
+			report.add(position, issueSelfConflict, "%s: '%s' steht in der eigenen Liste und wird ignoriert", where, name)
+		case seen[name]:
+			report.add(position, issueDuplicate, "%s: '%s' steht mehrfach in der Liste", where, name)
+		default:
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// checkKnownKeys meldet Schlüssel einer Tabelle, die das Programm nicht kennt (z.B. Tippfehler wie 'hoechsten'),
+// 		mit einem Vorschlag aus den bekannten Schlüsseln.
+func checkKnownKeys(table *toml.Tree, known []string, where string, report *validationReport) {
+	isKnown := make(map[string]bool)
+	for _, key := range known {
+		isKnown[key] = true
+	}
+	for _, key := range table.Keys() {
+		if isKnown[key] {
+			continue
+		}
+		message := fmt.Sprintf("%s: '%s' ist unbekannt und wird ignoriert. Erlaubt sind: %s", where, key, strings.Join(known, ", "))
+		if suggestions := suggestNames(key, known); len(suggestions) > 0 {
+			message += fmt.Sprintf(". Meintest du '%s'?", suggestions[0])
+		}
+		position := table.GetPositionPath([]string{key})
+		if position.Invalid() {
+			position = table.Position()
+		}
+		report.add(position, issueUnknownKey, "%s", message)
+	}
+}